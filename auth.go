@@ -0,0 +1,263 @@
+package astra
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
+	"google.golang.org/grpc/metadata"
+)
+
+// tokenHeader is the request metadata key of the auth token.
+const tokenHeader = "x-cassandra-token"
+
+const (
+	defaultTokenTTL           = 30 * time.Minute
+	defaultTokenRefreshBefore = 5 * time.Minute
+	defaultAuthTimeout        = 5 * time.Second
+
+	// tokenRefreshRetryDelay is how long to wait before retrying a failed
+	// proactive token refresh.
+	tokenRefreshRetryDelay = 5 * time.Second
+)
+
+// TokenProvider provides the auth tokens sent with requests. Use
+// WithTokenProvider to install one on a Client. Implementations must be safe
+// for concurrent use.
+type TokenProvider interface {
+	// Token returns the token to send with a request.
+	Token(ctx context.Context) (string, error)
+	// Invalidate is called with a token the server rejected as
+	// unauthenticated. Subsequent calls to Token should not return it.
+	Invalidate(token string)
+}
+
+// staticTokenProvider provides a single token.
+type staticTokenProvider string
+
+func (p staticTokenProvider) Token(context.Context) (string, error) {
+	return string(p), nil
+}
+
+func (p staticTokenProvider) Invalidate(string) {}
+
+// TableBasedTokenProvider provides tokens obtained from the Stargate table
+// auth API. See [Astra DB Table-based authentication/authorization].
+//
+// Tokens are cached until they are about to expire, and refreshed in the
+// background once they are within RefreshBefore of expiring, so that requests
+// do not wait for the auth API. Concurrent requests for a token share a
+// single call to the auth API.
+//
+// [Astra DB Table-based authentication/authorization]: https://stargate.io/docs/stargate/1.0/developers-guide/authnz.html#_table_based_authenticationauthorization
+type TableBasedTokenProvider struct {
+	// ServiceURL is the URL of the auth API, e.g.
+	// "http://localhost:8081/v1/auth".
+	ServiceURL string
+	// Username and Password are the credentials with which to authenticate.
+	Username string
+	Password string
+	// TTL is how long tokens remain valid after they are obtained. Defaults
+	// to 30 minutes, the Stargate default.
+	TTL time.Duration
+	// RefreshBefore is how long before tokens expire they are refreshed.
+	// Defaults to 5 minutes, and is capped at half the TTL.
+	RefreshBefore time.Duration
+	// HTTPClient is the client with which to call the auth API. Defaults to
+	// a client with a 5 second timeout.
+	HTTPClient *http.Client
+
+	mu         sync.Mutex
+	token      string
+	expires    time.Time
+	refreshAt  time.Time
+	refreshing chan struct{}
+	err        error
+
+	now func() time.Time
+}
+
+// NewTableBasedTokenProvider creates a new TableBasedTokenProvider obtaining
+// tokens from the auth API at serviceURL with the specified username and
+// password.
+func NewTableBasedTokenProvider(serviceURL, username, password string) *TableBasedTokenProvider {
+	return &TableBasedTokenProvider{
+		ServiceURL: serviceURL,
+		Username:   username,
+		Password:   password,
+	}
+}
+
+// Token returns the cached token, obtaining a new one if it has expired or
+// been invalidated.
+func (p *TableBasedTokenProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	now := p.clock()
+	if p.token != "" && now.Before(p.expires) {
+		token := p.token
+		if !now.Before(p.refreshAt) && p.refreshing == nil {
+			p.refreshing = make(chan struct{})
+			go p.refresh(p.refreshing)
+		}
+		p.mu.Unlock()
+		return token, nil
+	}
+
+	wait := p.refreshing
+	if wait == nil {
+		wait = make(chan struct{})
+		p.refreshing = wait
+		// Refresh in the background so that other callers waiting for the
+		// token are unaffected if ctx is canceled.
+		go p.refresh(wait)
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-wait:
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// The refresh may have failed, leaving the token which had expired.
+	if p.token == "" || !p.clock().Before(p.expires) {
+		if p.err == nil {
+			return "", fmt.Errorf("failed to get auth token: token expired")
+		}
+		return "", p.err
+	}
+	return p.token, nil
+}
+
+// Invalidate discards token if it is the cached token, so that the next call
+// to Token obtains a new one.
+func (p *TableBasedTokenProvider) Invalidate(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token == token {
+		p.token = ""
+	}
+}
+
+// refresh obtains a new token and closes done. It does not use the context
+// of the caller, which may be canceled while other callers are waiting, and
+// is bounded by the default auth timeout instead.
+func (p *TableBasedTokenProvider) refresh(done chan struct{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultAuthTimeout)
+	defer cancel()
+	token, err := p.fetch(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.clock()
+	if err != nil {
+		p.err = fmt.Errorf("failed to get auth token: %w", err)
+		p.refreshAt = now.Add(tokenRefreshRetryDelay)
+	} else {
+		ttl := p.TTL
+		if ttl <= 0 {
+			ttl = defaultTokenTTL
+		}
+		before := p.RefreshBefore
+		if before <= 0 {
+			before = defaultTokenRefreshBefore
+		}
+		if before > ttl/2 {
+			before = ttl / 2
+		}
+		p.token, p.err = token, nil
+		p.expires = now.Add(ttl)
+		p.refreshAt = p.expires.Add(-before)
+	}
+	p.refreshing = nil
+	close(done)
+}
+
+// fetch obtains a new token from the auth API.
+func (p *TableBasedTokenProvider) fetch(ctx context.Context) (string, error) {
+	body, err := json.Marshal(struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}{p.Username, p.Password})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.ServiceURL, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := p.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: defaultAuthTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call auth service: %w", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("auth service returned %s: %s", resp.Status, bytes.TrimSpace(b))
+	}
+
+	var ar struct {
+		AuthToken string `json:"authToken"`
+	}
+	if err := json.Unmarshal(b, &ar); err != nil {
+		return "", fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if ar.AuthToken == "" {
+		return "", fmt.Errorf("auth service returned no token")
+	}
+	return ar.AuthToken, nil
+}
+
+func (p *TableBasedTokenProvider) clock() time.Time {
+	if p.now != nil {
+		return p.now()
+	}
+	return time.Now()
+}
+
+// authenticatedAttempt performs req once, as by attempt, sending a token
+// from the token provider. If the server rejects the token as
+// unauthenticated, the token is invalidated and, if the token provider then
+// provides a different token, req is attempted once more with it.
+func (c *Client) authenticatedAttempt(ctx context.Context, p *params, req stargateRequest) (*pb.Response, error) {
+	if c.tokenProvider == nil {
+		return c.attempt(ctx, p, req)
+	}
+	token, err := c.tokenProvider.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auth token: %w", err)
+	}
+	resp, err := c.attempt(metadata.AppendToOutgoingContext(ctx, tokenHeader, token), p, req)
+	if !errors.Is(err, ErrUnauthenticated) {
+		return resp, err
+	}
+
+	c.tokenProvider.Invalidate(token)
+	next, terr := c.tokenProvider.Token(ctx)
+	if terr != nil {
+		return nil, fmt.Errorf("failed to get auth token: %w", terr)
+	}
+	if next == token {
+		// Retrying would send the rejected token again.
+		return resp, err
+	}
+	return c.attempt(metadata.AppendToOutgoingContext(ctx, tokenHeader, next), p, req)
+}
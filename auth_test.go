@@ -0,0 +1,221 @@
+package astra
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// authServer returns a test auth API server issuing tokens "token-1",
+// "token-2", etc., and the number of tokens it has issued.
+func authServer(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+	var n int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Username != "user" || req.Password != "pass" {
+			http.Error(w, "bad credentials", http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"authToken": "token-%d"}`, atomic.AddInt32(&n, 1))
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &n
+}
+
+func TestTableBasedTokenProvider(t *testing.T) {
+	ts, issued := authServer(t)
+	now := time.Now()
+	var mu sync.Mutex
+	p := NewTableBasedTokenProvider(ts.URL, "user", "pass")
+	p.TTL = 10 * time.Minute
+	p.RefreshBefore = time.Minute
+	p.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		now = now.Add(d)
+	}
+	token := func() string {
+		t.Helper()
+		tok, err := p.Token(context.Background())
+		if err != nil {
+			t.Fatalf("Token() unexpected error: %v", err)
+		}
+		return tok
+	}
+
+	// Concurrent callers share a single call to the auth API.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := token(); got != "token-1" {
+				t.Errorf("Token() got %q, want %q", got, "token-1")
+			}
+		}()
+	}
+	wg.Wait()
+	if got := atomic.LoadInt32(issued); got != 1 {
+		t.Errorf("got %d auth API calls, want 1", got)
+	}
+
+	// Tokens within the refresh window are returned while a new one is
+	// obtained in the background.
+	advance(9*time.Minute + 30*time.Second)
+	if got := token(); got != "token-1" {
+		t.Errorf("Token() in refresh window got %q, want %q", got, "token-1")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for token() != "token-2" {
+		if time.Now().After(deadline) {
+			t.Fatalf("Token() was not refreshed in the background")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Expired tokens are replaced before returning.
+	advance(11 * time.Minute)
+	if got := token(); got != "token-3" {
+		t.Errorf("Token() after expiry got %q, want %q", got, "token-3")
+	}
+
+	// Invalidating a stale token has no effect.
+	p.Invalidate("token-2")
+	if got := token(); got != "token-3" {
+		t.Errorf("Token() after invalidating stale token got %q, want %q", got, "token-3")
+	}
+	p.Invalidate("token-3")
+	if got := token(); got != "token-4" {
+		t.Errorf("Token() after invalidation got %q, want %q", got, "token-4")
+	}
+}
+
+func TestTableBasedTokenProvider_error(t *testing.T) {
+	ts, _ := authServer(t)
+	p := NewTableBasedTokenProvider(ts.URL, "user", "wrong")
+	if _, err := p.Token(context.Background()); err == nil {
+		t.Errorf("Token() got nil error for bad credentials, want error")
+	}
+
+	// An expired token is not returned when refreshing it fails.
+	now := time.Now()
+	p.now = func() time.Time { return now }
+	p.Password = "pass"
+	if _, err := p.Token(context.Background()); err != nil {
+		t.Fatalf("Token() unexpected error: %v", err)
+	}
+	p.Password = "wrong"
+	now = now.Add(defaultTokenTTL + time.Minute)
+	if got, err := p.Token(context.Background()); err == nil {
+		t.Errorf("Token() after failed refresh of expired token got %q, want error", got)
+	}
+}
+
+// fakeTokenProvider issues tokens "token-1", "token-2", etc., replacing the
+// current token when it is invalidated unless static is set.
+type fakeTokenProvider struct {
+	static bool
+
+	mu          sync.Mutex
+	n           int
+	invalidated []string
+}
+
+func (p *fakeTokenProvider) Token(context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.n == 0 {
+		p.n = 1
+	}
+	return fmt.Sprintf("token-%d", p.n), nil
+}
+
+func (p *fakeTokenProvider) Invalidate(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.invalidated = append(p.invalidated, token)
+	if !p.static {
+		p.n++
+	}
+}
+
+func TestClient_reauthenticate(t *testing.T) {
+	unauthenticated := status.Error(codes.Unauthenticated, "expired token")
+
+	tests := []struct {
+		name            string
+		errs            []error
+		static          bool
+		wantErr         error
+		wantTokens      []string
+		wantInvalidated []string
+	}{
+		{
+			name:       "authenticated",
+			wantTokens: []string{"token-1"},
+		},
+		{
+			name:            "retried once",
+			errs:            []error{unauthenticated},
+			wantTokens:      []string{"token-1", "token-2"},
+			wantInvalidated: []string{"token-1"},
+		},
+		{
+			name:            "fails after retry",
+			errs:            []error{unauthenticated, unauthenticated},
+			wantErr:         ErrUnauthenticated,
+			wantTokens:      []string{"token-1", "token-2"},
+			wantInvalidated: []string{"token-1"},
+		},
+		{
+			name:            "not retried with same token",
+			errs:            []error{unauthenticated, unauthenticated},
+			static:          true,
+			wantErr:         ErrUnauthenticated,
+			wantTokens:      []string{"token-1"},
+			wantInvalidated: []string{"token-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeStargateClient{errs: tt.errs}
+			tp := &fakeTokenProvider{static: tt.static}
+			c := &Client{sgClient: fake, timeout: time.Second, tokenProvider: tp}
+
+			// Not idempotent, since the rejected request was not executed.
+			_, err := c.Query("INSERT INTO t (id) VALUES (1)").Exec()
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Exec() unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Exec() got error %v, want %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantTokens, fake.tokens); diff != "" {
+				t.Errorf("sent tokens mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantInvalidated, tp.invalidated); diff != "" {
+				t.Errorf("invalidated tokens mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
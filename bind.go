@@ -0,0 +1,81 @@
+package astra
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/datastax-ext/astra-go-sdk/internal/cqlscan"
+)
+
+// bindNamed rewrites the named bind markers in cql, e.g. ":name", into
+// positional bind markers, and returns the rewritten CQL along with the values
+// for the positional markers taken from named or, failing that, optional.
+// Markers inside string literals, quoted identifiers and comments are left
+// untouched. A name may be used by more than one marker.
+//
+// It returns an error if a marker has no value in either map, or if a value
+// in named is not used by any marker.
+func bindNamed(cql string, named, optional map[string]any) (string, []any, error) {
+	var (
+		b       strings.Builder
+		values  []any
+		used    = make(map[string]bool, len(named))
+		missing []string
+	)
+	b.Grow(len(cql))
+	for i := 0; i < len(cql); {
+		kind, j := cqlscan.Next(cql, i)
+		if kind == cqlscan.Other && cql[i] == ':' && j < len(cql) && isIdentStart(cql[j]) {
+			for j < len(cql) && isIdentPart(cql[j]) {
+				j++
+			}
+			name := cql[i+1 : j]
+			if v, ok := named[name]; ok {
+				values = append(values, v)
+				used[name] = true
+			} else if v, ok := optional[name]; ok {
+				values = append(values, v)
+			} else if !contains(missing, name) {
+				missing = append(missing, name)
+			}
+			b.WriteByte('?')
+			i = j
+			continue
+		}
+		b.WriteString(cql[i:j])
+		i = j
+	}
+
+	if len(missing) > 0 {
+		return "", nil, fmt.Errorf("missing values for named parameters: %s", strings.Join(missing, ", "))
+	}
+	if len(used) < len(named) {
+		var unused []string
+		for name := range named {
+			if !used[name] {
+				unused = append(unused, name)
+			}
+		}
+		sort.Strings(unused)
+		return "", nil, fmt.Errorf("unused named values: %s", strings.Join(unused, ", "))
+	}
+	return b.String(), values, nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || '0' <= c && c <= '9'
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package astra
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBindNamed(t *testing.T) {
+	tests := []struct {
+		name       string
+		cql        string
+		named      map[string]any
+		wantCQL    string
+		wantValues []any
+		wantErr    bool
+	}{
+		{
+			name:       "simple",
+			cql:        `UPDATE users SET name = :name, age = :age WHERE id = :id`,
+			named:      map[string]any{"id": 1, "name": "Alice", "age": 30},
+			wantCQL:    `UPDATE users SET name = ?, age = ? WHERE id = ?`,
+			wantValues: []any{"Alice", 30, 1},
+		},
+		{
+			name:       "repeated name",
+			cql:        `SELECT * FROM events WHERE day = :day AND ts >= :day_start AND day2 = :day`,
+			named:      map[string]any{"day": "mon", "day_start": 5},
+			wantCQL:    `SELECT * FROM events WHERE day = ? AND ts >= ? AND day2 = ?`,
+			wantValues: []any{"mon", 5, "mon"},
+		},
+		{
+			name:       "no space",
+			cql:        `INSERT INTO t (a,b) VALUES (:a,:b)`,
+			named:      map[string]any{"a": 1, "b": 2},
+			wantCQL:    `INSERT INTO t (a,b) VALUES (?,?)`,
+			wantValues: []any{1, 2},
+		},
+		{
+			name: "quoted and commented",
+			cql: `INSERT INTO t ("x:y", m) VALUES (:x, {'k:v': 'it''s :not'}) -- :comment
+				/* :block */ USING TTL $$:dollar$$`,
+			named: map[string]any{"x": 1},
+			wantCQL: `INSERT INTO t ("x:y", m) VALUES (?, {'k:v': 'it''s :not'}) -- :comment
+				/* :block */ USING TTL $$:dollar$$`,
+			wantValues: []any{1},
+		},
+		{
+			name:    "missing",
+			cql:     `SELECT * FROM users WHERE id = :id AND name = :name`,
+			named:   map[string]any{"id": 1},
+			wantErr: true,
+		},
+		{
+			name:    "unused",
+			cql:     `SELECT * FROM users WHERE id = :id`,
+			named:   map[string]any{"id": 1, "name": "Alice"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cql, values, err := bindNamed(tt.cql, tt.named, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("bindNamed() got nil error, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("bindNamed() unexpected error: %v", err)
+			}
+			if cql != tt.wantCQL {
+				t.Errorf("bindNamed() got CQL %q, want %q", cql, tt.wantCQL)
+			}
+			if diff := cmp.Diff(tt.wantValues, values); diff != "" {
+				t.Errorf("bindNamed() values mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestQuery_Bind(t *testing.T) {
+	c := &Client{}
+	q := c.Query(`UPDATE users SET name = :name WHERE id = :id`).
+		Bind(map[string]any{"id": 1}).
+		Bind(map[string]any{"name": "Alice"})
+	got, err := q.toQueryProto()
+	if err != nil {
+		t.Fatalf("toQueryProto() unexpected error: %v", err)
+	}
+	if want := `UPDATE users SET name = ? WHERE id = ?`; got.Cql != want {
+		t.Errorf("toQueryProto() got CQL %q, want %q", got.Cql, want)
+	}
+	if len(got.Values.Values) != 2 {
+		t.Errorf("toQueryProto() got %d values, want 2", len(got.Values.Values))
+	}
+
+	q = c.Query(`SELECT * FROM users WHERE id = :id`, 1).Bind(map[string]any{"id": 1})
+	if _, err := q.toQueryProto(); err == nil {
+		t.Errorf("toQueryProto() with positional and named values got nil error, want error")
+	}
+}
@@ -2,38 +2,163 @@ package astra
 
 import (
 	"archive/zip"
+	"bytes"
 	"crypto/tls"
 	"crypto/x509"
-	"encoding/json"
+	"encoding/base64"
 	"fmt"
 	"io"
+	"net"
+	"os"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
 )
 
-const astraURLSuffix = "apps.astra.datastax.com:443"
+const (
+	astraHostSuffix = "apps.astra.datastax.com"
+	astraPort       = 443
+)
 
-type bundle struct {
-	tlsConfig *tls.Config
-	host      string
-	port      int
+// Bundle is the connection configuration read from a secure connect bundle.
+type Bundle struct {
+	// Host is the host name of the Stargate gRPC service of the database.
+	Host string
+	// Port is the port of the Stargate gRPC service of the database.
+	Port int
+	// TLSConfig is the TLS configuration for connections to the database,
+	// using the CA certificate and client certificate of the bundle.
+	TLSConfig *tls.Config
+	// NotAfter is the time at which the first of the client and CA
+	// certificates of the bundle expires.
+	NotAfter time.Time
 }
 
-func loadBundleZip(reader *zip.Reader) (*bundle, error) {
-	contents, err := extract(reader)
+// Addr returns the address of the Stargate gRPC service, e.g.
+// "<cluster ID>-<cluster region>.apps.astra.datastax.com:443".
+func (b *Bundle) Addr() string {
+	return net.JoinHostPort(b.Host, strconv.Itoa(b.Port))
+}
+
+// BundleAddrResolver returns the host and port of the Stargate gRPC service to
+// dial for the host and port in the config.json of a secure connect bundle.
+type BundleAddrResolver func(host string, port int) (string, int, error)
+
+// BundleOption is an option for loading a secure connect bundle, determining
+// the address of the Stargate gRPC service to dial. By default, the first
+// label of the host in config.json, the database ID and region, is joined
+// with "apps.astra.datastax.com" and dialed on port 443, e.g.
+// "<cluster ID>-<cluster region>.apps.astra.datastax.com:443".
+type BundleOption func(*bundleOptions)
+
+type bundleOptions struct {
+	suffix     string
+	configPort bool
+	exact      bool
+	resolver   BundleAddrResolver
+}
+
+func newBundleOptions(opts []BundleOption) *bundleOptions {
+	o := &bundleOptions{suffix: astraHostSuffix}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// BundleHostSuffix replaces "apps.astra.datastax.com" as the domain joined
+// with the database ID and region, e.g. for private link endpoints.
+func BundleHostSuffix(suffix string) BundleOption {
+	return func(o *bundleOptions) {
+		o.suffix = strings.Trim(suffix, ".")
+	}
+}
+
+// BundleConfigPort dials the port in config.json rather than port 443.
+func BundleConfigPort() BundleOption {
+	return func(o *bundleOptions) {
+		o.configPort = true
+	}
+}
+
+// BundleExactHost dials the host and port in config.json as given, e.g. for
+// self-hosted Stargate with bundle-style TLS.
+func BundleExactHost() BundleOption {
+	return func(o *bundleOptions) {
+		o.exact = true
+	}
+}
+
+// BundleResolver determines the address to dial with resolve, taking
+// precedence over the other options. The server certificate is still verified
+// against the host in config.json, so resolve may return e.g. an IP address
+// or the host of a proxy.
+func BundleResolver(resolve BundleAddrResolver) BundleOption {
+	return func(o *bundleOptions) {
+		o.resolver = resolve
+	}
+}
+
+// usesConfigPort reports whether the port in config.json is dialed, rather
+// than ignored or passed to a resolver whose result is validated instead.
+func (o *bundleOptions) usesConfigPort() bool {
+	return o.resolver == nil && (o.exact || o.configPort)
+}
+
+// serverName returns the host name to verify the server certificate against,
+// given the host in config.json and the host resolved from it.
+func (o *bundleOptions) serverName(configHost, host string) string {
+	if o.resolver != nil {
+		return configHost
+	}
+	return host
+}
+
+// resolve returns the address to dial for the host and port in config.json.
+func (o *bundleOptions) resolve(host string, port int) (string, int, error) {
+	if o.resolver != nil {
+		return o.resolver(host, port)
+	}
+	if o.exact {
+		return host, port, nil
+	}
+	strs := strings.Split(host, ".")
+	if len(strs) < 2 || strs[0] == "" {
+		return "", 0, fmt.Errorf("cannot derive Astra host from %q, which is not a fully qualified domain name", host)
+	}
+	if !o.configPort {
+		port = astraPort
+	}
+	return fmt.Sprintf("%s.%s", strs[0], o.suffix), port, nil
+}
+
+// LoadBundle reads the secure connect bundle at path. It returns a
+// *BundleError if the bundle fails validation, as described by
+// ValidateBundle.
+func LoadBundle(path string, opts ...BundleOption) (*Bundle, error) {
+	return loadBundleZipFromPath(path, opts)
+}
+
+// LoadBundleReader reads a secure connect bundle of the given size from r,
+// e.g. a *bytes.Reader.
+func LoadBundleReader(r io.ReaderAt, size int64, opts ...BundleOption) (*Bundle, error) {
+	reader, err := zip.NewReader(r, size)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
 	}
+	return loadBundleZip(reader, opts)
+}
 
-	config := struct {
-		Host             string `json:"host"`
-		Port             int    `json:"port"`
-		KeyStorePassword string `json:"keyStorePassword"`
-	}{}
-	err = json.Unmarshal(contents["config.json"], &config)
+func loadBundleZip(reader *zip.Reader, opts []BundleOption) (*Bundle, error) {
+	contents, err := extract(reader)
 	if err != nil {
 		return nil, err
 	}
+	report, bc := validateBundle(contents, time.Now(), newBundleOptions(opts))
+	if err := report.Err(); err != nil {
+		return nil, err
+	}
 
 	// rootCAs := x509.NewCertPool()
 	rootCAs, err := createCertPool()
@@ -41,34 +166,24 @@ func loadBundleZip(reader *zip.Reader) (*bundle, error) {
 		return nil, err
 	}
 
-	ok := rootCAs.AppendCertsFromPEM(contents["ca.crt"])
+	ok := rootCAs.AppendCertsFromPEM(bc.caPEM)
 	if !ok {
 		return nil, fmt.Errorf("the provided CA cert could not be added to the root CA pool")
 	}
 
-	cert, err := tls.X509KeyPair(contents["cert"], contents["key"])
-	if err != nil {
-		return nil, err
-	}
-
-	var astraURI string
-	if strs := strings.Split(config.Host, "."); len(strs) > 1 {
-		astraURI = fmt.Sprintf("%s.%s", strs[0], astraURLSuffix)
-	} else {
-		return nil, fmt.Errorf("invalid host name: %s", config.Host)
-	}
-
-	return &bundle{
-		tlsConfig: &tls.Config{
+	return &Bundle{
+		Host: bc.host,
+		Port: bc.port,
+		TLSConfig: &tls.Config{
 			RootCAs:      rootCAs,
-			Certificates: []tls.Certificate{cert},
-			ServerName:   strings.Split(astraURI, ":")[0],
+			Certificates: []tls.Certificate{bc.cert},
+			ServerName:   bc.serverName,
 		},
-		host: astraURI,
+		NotAfter: report.NotAfter,
 	}, nil
 }
 
-func loadBundleZipFromPath(path string) (*bundle, error) {
+func loadBundleZipFromPath(path string, opts []BundleOption) (*Bundle, error) {
 	reader, err := zip.OpenReader(path)
 	if err != nil {
 		return nil, err
@@ -78,27 +193,37 @@ func loadBundleZipFromPath(path string) (*bundle, error) {
 		_ = reader.Close()
 	}(reader)
 
-	return loadBundleZip(&reader.Reader)
+	return loadBundleZip(&reader.Reader, opts)
+}
+
+func loadBundleZipFromBytes(b []byte, opts []BundleOption) (*Bundle, error) {
+	return LoadBundleReader(bytes.NewReader(b), int64(len(b)), opts...)
+}
+
+func loadBundleZipFromEnv(name string, opts []BundleOption) (*Bundle, error) {
+	v, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("environment variable %s is not set", name)
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(v))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode environment variable %s: %w", name, err)
+	}
+	return loadBundleZipFromBytes(b, opts)
 }
 
 func extract(reader *zip.Reader) (map[string][]byte, error) {
 	contents := make(map[string][]byte)
 
 	for _, file := range reader.File {
-		switch file.Name {
-		case "config.json", "cert", "key", "ca.crt":
-			bytes, err := loadBytes(file)
-			if err != nil {
-				return nil, err
-			}
-			contents[file.Name] = bytes
+		if !contains(bundleFiles, file.Name) {
+			continue
 		}
-	}
-
-	for _, file := range []string{"config.json", "cert", "key", "ca.crt"} {
-		if _, ok := contents[file]; !ok {
-			return nil, fmt.Errorf("bundle missing '%s' file", file)
+		bytes, err := loadBytes(file)
+		if err != nil {
+			return nil, err
 		}
+		contents[file.Name] = bytes
 	}
 
 	return contents, nil
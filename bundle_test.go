@@ -0,0 +1,230 @@
+package astra
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testBundle returns a secure connect bundle for host, with a client
+// certificate signed by a generated CA which expires at notAfter.
+func testBundle(t *testing.T, host string, notAfter time.Time) []byte {
+	t.Helper()
+	return zipBundle(t, testBundleFiles(t, host, notAfter))
+}
+
+// testBundleFiles returns the files of a bundle as created by testBundle.
+func testBundleFiles(t *testing.T, host string, notAfter time.Time) map[string][]byte {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate CA key: %v", err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create CA certificate: %v", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate client key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, tmpl, caTmpl, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("failed to create client certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal client key: %v", err)
+	}
+
+	return map[string][]byte{
+		"config.json": []byte(fmt.Sprintf(`{"host": %q, "port": 29042}`, host)),
+		"ca.crt":      pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+		"cert":        pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+		"key":         pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// zipBundle returns a secure connect bundle holding files.
+func zipBundle(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, b := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+		if _, err := f.Write(b); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close bundle: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestLoadBundle(t *testing.T) {
+	const host = "0123-us-east1.db.astra.datastax.com"
+	b := testBundle(t, host, time.Now().Add(24*time.Hour))
+
+	path := filepath.Join(t.TempDir(), "secure-connect-bundle.zip")
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		t.Fatalf("failed to write bundle: %v", err)
+	}
+	const env = "ASTRA_GO_SDK_TEST_SCB"
+	t.Setenv(env, base64.StdEncoding.EncodeToString(b))
+
+	loaders := map[string]func() (*Bundle, error){
+		"path":   func() (*Bundle, error) { return LoadBundle(path) },
+		"reader": func() (*Bundle, error) { return LoadBundleReader(bytes.NewReader(b), int64(len(b))) },
+		"bytes":  func() (*Bundle, error) { return loadBundleZipFromBytes(b, nil) },
+		"env":    func() (*Bundle, error) { return loadBundleZipFromEnv(env, nil) },
+	}
+	for name, load := range loaders {
+		t.Run(name, func(t *testing.T) {
+			got, err := load()
+			if err != nil {
+				t.Fatalf("failed to load bundle: %v", err)
+			}
+			if want := "0123-us-east1.apps.astra.datastax.com:443"; got.Addr() != want {
+				t.Errorf("Addr() got %q, want %q", got.Addr(), want)
+			}
+			if got.TLSConfig.ServerName != got.Host {
+				t.Errorf("TLSConfig.ServerName got %q, want %q", got.TLSConfig.ServerName, got.Host)
+			}
+			if len(got.TLSConfig.Certificates) != 1 {
+				t.Errorf("TLSConfig.Certificates got %d certificates, want 1", len(got.TLSConfig.Certificates))
+			}
+		})
+	}
+}
+
+func TestLoadBundle_errors(t *testing.T) {
+	const env = "ASTRA_GO_SDK_TEST_SCB"
+	t.Setenv(env, "not base64!")
+	if _, err := loadBundleZipFromEnv(env, nil); err == nil {
+		t.Errorf("loadBundleZipFromEnv() got nil error for invalid base64, want error")
+	}
+	if _, err := loadBundleZipFromEnv(env+"_UNSET", nil); err == nil {
+		t.Errorf("loadBundleZipFromEnv() got nil error for unset variable, want error")
+	}
+	if _, err := loadBundleZipFromBytes([]byte("not a zip"), nil); err == nil {
+		t.Errorf("loadBundleZipFromBytes() got nil error for invalid zip, want error")
+	}
+}
+
+func TestLoadBundle_options(t *testing.T) {
+	b := testBundle(t, "0123-us-east1.db.astra.datastax.com", time.Now().Add(24*time.Hour))
+
+	tests := []struct {
+		name           string
+		opts           []BundleOption
+		want           string
+		wantServerName string
+		wantErr        bool
+	}{
+		{
+			name: "default",
+			want: "0123-us-east1.apps.astra.datastax.com:443",
+		},
+		{
+			name: "host suffix",
+			opts: []BundleOption{BundleHostSuffix(".private.example.com")},
+			want: "0123-us-east1.private.example.com:443",
+		},
+		{
+			name: "host suffix and config port",
+			opts: []BundleOption{BundleHostSuffix("private.example.com"), BundleConfigPort()},
+			want: "0123-us-east1.private.example.com:29042",
+		},
+		{
+			name: "exact host",
+			opts: []BundleOption{BundleExactHost()},
+			want: "0123-us-east1.db.astra.datastax.com:29042",
+		},
+		{
+			name: "resolver",
+			opts: []BundleOption{BundleExactHost(), BundleResolver(func(host string, port int) (string, int, error) {
+				return "10.0.0.1", port + 1, nil
+			})},
+			want:           "10.0.0.1:29043",
+			wantServerName: "0123-us-east1.db.astra.datastax.com",
+		},
+		{
+			name: "resolver error",
+			opts: []BundleOption{BundleResolver(func(string, int) (string, int, error) {
+				return "", 0, fmt.Errorf("unknown host")
+			})},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadBundleZipFromBytes(b, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadBundleZipFromBytes() got error %v, want error %t", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Addr() != tt.want {
+				t.Errorf("Addr() got %q, want %q", got.Addr(), tt.want)
+			}
+			wantServerName := tt.wantServerName
+			if wantServerName == "" {
+				wantServerName = got.Host
+			}
+			if got.TLSConfig.ServerName != wantServerName {
+				t.Errorf("TLSConfig.ServerName got %q, want %q", got.TLSConfig.ServerName, wantServerName)
+			}
+		})
+	}
+}
+
+func TestLoadBundle_exactUnqualifiedHost(t *testing.T) {
+	b := testBundle(t, "localhost", time.Now().Add(24*time.Hour))
+	if _, err := loadBundleZipFromBytes(b, nil); err == nil {
+		t.Errorf("loadBundleZipFromBytes() got nil error deriving Astra host from unqualified host, want error")
+	}
+	got, err := loadBundleZipFromBytes(b, []BundleOption{BundleExactHost()})
+	if err != nil {
+		t.Fatalf("loadBundleZipFromBytes() unexpected error: %v", err)
+	}
+	if want := "localhost:29042"; got.Addr() != want {
+		t.Errorf("Addr() got %q, want %q", got.Addr(), want)
+	}
+}
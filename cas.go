@@ -0,0 +1,65 @@
+package astra
+
+import (
+	"fmt"
+)
+
+// casRow returns the first row of the result of a conditional statement and
+// the index of its [applied] column.
+func casRow(res *Result) (*Row, int, error) {
+	if len(res.Rows) == 0 {
+		return nil, 0, fmt.Errorf("result has no rows, is the statement conditional?")
+	}
+	row := &res.Rows[0]
+	idx := row.Index(appliedColumn)
+	if idx < 0 {
+		return nil, 0, fmt.Errorf("result has no %s column, is the statement conditional?", appliedColumn)
+	}
+	return row, idx, nil
+}
+
+// scanCAS reports whether the conditional statement which produced res was
+// applied, and copies the existing values returned by the server, if any,
+// into dest.
+func scanCAS(res *Result, dest []any) (bool, error) {
+	row, idx, err := casRow(res)
+	if err != nil {
+		return false, err
+	}
+	if len(row.values) == 1 || len(dest) == 0 {
+		return res.Applied, nil
+	}
+	if len(row.values)-1 != len(dest) {
+		return false, fmt.Errorf("result has %d existing values, got %d pointers", len(row.values)-1, len(dest))
+	}
+	i := 0
+	for j, v := range row.values {
+		if j == idx {
+			continue
+		}
+		if err := convertAssign(dest[i], v); err != nil {
+			return false, fmt.Errorf("failed to assign existing column %q: %w", row.spec.names[j], err)
+		}
+		i++
+	}
+	return res.Applied, nil
+}
+
+// mapScanCAS reports whether the conditional statement which produced res was
+// applied, and stores the existing values returned by the server, if any, in
+// dest keyed by column name. The values are discarded if dest is nil.
+func mapScanCAS(res *Result, dest map[string]any) (bool, error) {
+	row, idx, err := casRow(res)
+	if err != nil {
+		return false, err
+	}
+	if dest == nil {
+		return res.Applied, nil
+	}
+	for j, v := range row.values {
+		if j != idx {
+			dest[row.spec.names[j]] = v
+		}
+	}
+	return res.Applied, nil
+}
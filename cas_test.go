@@ -0,0 +1,100 @@
+package astra
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
+)
+
+func casResponse(applied bool, existing map[string]*pb.Value) *pb.Response {
+	rs := &pb.ResultSet{
+		Columns: []*pb.ColumnSpec{{Name: "[applied]", Type: &pb.TypeSpec{Spec: &pb.TypeSpec_Basic_{Basic: pb.TypeSpec_BOOLEAN}}}},
+	}
+	row := &pb.Row{Values: []*pb.Value{{Inner: &pb.Value_Boolean{Boolean: applied}}}}
+	for name, v := range existing {
+		rs.Columns = append(rs.Columns, &pb.ColumnSpec{Name: name, Type: &pb.TypeSpec{Spec: &pb.TypeSpec_Basic_{Basic: pb.TypeSpec_INT}}})
+		row.Values = append(row.Values, v)
+	}
+	rs.Rows = []*pb.Row{row}
+	return &pb.Response{Result: &pb.Response_ResultSet{ResultSet: rs}}
+}
+
+func TestQuery_ExecCAS(t *testing.T) {
+	existing := map[string]*pb.Value{"version": {Inner: &pb.Value_Int{Int: 3}}}
+
+	t.Run("applied", func(t *testing.T) {
+		c := &Client{sgClient: &fakeStargateClient{resp: casResponse(true, nil)}, timeout: defaultTimeout}
+		var version int
+		applied, err := c.Query(`UPDATE docs SET version = 2 WHERE id = 1 IF version = 1`).ExecCAS(&version)
+		if err != nil {
+			t.Fatalf("ExecCAS() unexpected error: %v", err)
+		}
+		if !applied || version != 0 {
+			t.Errorf("ExecCAS() got applied %v, version %d, want true, 0", applied, version)
+		}
+	})
+
+	t.Run("not applied", func(t *testing.T) {
+		c := &Client{sgClient: &fakeStargateClient{resp: casResponse(false, existing)}, timeout: defaultTimeout}
+		var version int
+		applied, err := c.Query(`UPDATE docs SET version = 2 WHERE id = 1 IF version = 1`).ExecCAS(&version)
+		if err != nil {
+			t.Fatalf("ExecCAS() unexpected error: %v", err)
+		}
+		if applied || version != 3 {
+			t.Errorf("ExecCAS() got applied %v, version %d, want false, 3", applied, version)
+		}
+	})
+
+	t.Run("map not applied", func(t *testing.T) {
+		c := &Client{sgClient: &fakeStargateClient{resp: casResponse(false, existing)}, timeout: defaultTimeout}
+		got := map[string]any{}
+		applied, err := c.Batch(c.Query(`UPDATE docs SET version = 2 WHERE id = 1 IF version = 1`)).MapExecCAS(got)
+		if err != nil {
+			t.Fatalf("MapExecCAS() unexpected error: %v", err)
+		}
+		if applied {
+			t.Errorf("MapExecCAS() got applied true, want false")
+		}
+		if diff := cmp.Diff(map[string]any{"version": int64(3)}, got); diff != "" {
+			t.Errorf("MapExecCAS() existing values mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("map nil", func(t *testing.T) {
+		c := &Client{sgClient: &fakeStargateClient{resp: casResponse(false, existing)}, timeout: defaultTimeout}
+		applied, err := c.Query(`UPDATE docs SET version = 2 WHERE id = 1 IF version = 1`).MapExecCAS(nil)
+		if err != nil {
+			t.Fatalf("MapExecCAS(nil) unexpected error: %v", err)
+		}
+		if applied {
+			t.Errorf("MapExecCAS(nil) got applied true, want false")
+		}
+	})
+
+	t.Run("wrong number of pointers", func(t *testing.T) {
+		c := &Client{sgClient: &fakeStargateClient{resp: casResponse(false, existing)}, timeout: defaultTimeout}
+		var a, b int
+		if _, err := c.Query(`UPDATE docs SET version = 2 WHERE id = 1 IF version = 1`).ExecCAS(&a, &b); err == nil {
+			t.Errorf("ExecCAS() got nil error, want error")
+		}
+	})
+
+	t.Run("not conditional", func(t *testing.T) {
+		c := &Client{sgClient: &fakeStargateClient{}, timeout: defaultTimeout}
+		if _, err := c.Query(`UPDATE docs SET version = 2 WHERE id = 1`).ExecCAS(); err == nil {
+			t.Errorf("ExecCAS() got nil error, want error")
+		}
+	})
+
+	t.Run("invalid serial consistency", func(t *testing.T) {
+		c := &Client{sgClient: &fakeStargateClient{resp: casResponse(true, nil)}, timeout: defaultTimeout}
+		_, err := c.Query(`UPDATE docs SET version = 2 WHERE id = 1 IF version = 1`).
+			SerialConsistency(ConsistencyQuorum).
+			ExecCAS()
+		if err == nil {
+			t.Errorf("ExecCAS() with QUORUM serial consistency got nil error, want error")
+		}
+	})
+}
@@ -3,24 +3,33 @@ package astra
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
-	"github.com/stargate/stargate-grpc-go-client/stargate/pkg/auth"
-	"github.com/stargate/stargate-grpc-go-client/stargate/pkg/client"
 	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 const (
-	defaultDeadline = time.Second * 10
-	defaultTimeout  = time.Second * 10
+	defaultDeadline    = time.Second * 10
+	defaultTimeout     = time.Second * 10
+	defaultGracePeriod = time.Second * 10
+
+	defaultBundleExpiryWarning = 30 * 24 * time.Hour
 )
 
+// ErrClientClosed is returned when executing queries with a Client which has
+// been closed.
+var ErrClientClosed = errors.New("client is closed")
+
 // To make testing with examples easier. Set to
 // grpc.WithTransportCredentials(insecure.NewCredentials()) for localhost
 // testing.
@@ -30,35 +39,63 @@ var defaultInsecureCredentials = grpc.WithTransportCredentials(credentials.NewTL
 type Client struct {
 	astraURI string
 
-	token string
+	tokenProvider TokenProvider
 
-	authServiceURL string
-	authUsername   string
-	authPassword   string
+	// loadBundle loads the secure connect bundle, if one is used.
+	loadBundle          func() (*Bundle, error)
+	bundlePath          string
+	bundleFixed         bool // the bundle is not read from a file, so cannot be watched
+	bundleExpiryWarning time.Duration
+	bundleWatch         time.Duration
+	bundleCreds         *reloadingCredentials
 
-	scbPath string
+	// expiryWarnedMu guards expiryWarned, the expiry time of the bundle
+	// certificates last warned about.
+	expiryWarnedMu sync.Mutex
+	expiryWarned   time.Time
 
 	deadline       time.Duration
 	timeout        time.Duration
 	tlsConfig      *tls.Config
 	insecure       bool
 	grpcConnParams *grpc.ConnectParams
+	gracePeriod    time.Duration
 
 	defaultQueryParams queryParams
+	timestampGenerator TimestampGenerator
+
+	schema     *Schema
+	schemaOnce sync.Once
 
-	sgClient *client.StargateClient
+	conn     *grpc.ClientConn
+	sgClient pb.StargateClient
+
+	mu        sync.Mutex
+	closed    bool
+	done      chan struct{}
+	inFlight  sync.WaitGroup
+	closeOnce sync.Once
+	closeErr  error
 }
 
 // NewStaticTokenClient creates a new Client which uses the specified static
 // auth token for requests.
 func NewStaticTokenClient(token string, connection StaticTokenConnectConfig, opts ...ClientOption) (*Client, error) {
+	return NewStaticTokenClientContext(context.Background(), token, connection, opts...)
+}
+
+// NewStaticTokenClientContext is like NewStaticTokenClient, but uses the
+// provided context for the initial connection.
+func NewStaticTokenClientContext(ctx context.Context, token string, connection StaticTokenConnectConfig, opts ...ClientOption) (*Client, error) {
 	c := &Client{
-		token:    token,
-		deadline: defaultDeadline,
-		timeout:  defaultTimeout,
+		tokenProvider:       staticTokenProvider(token),
+		deadline:            defaultDeadline,
+		timeout:             defaultTimeout,
+		gracePeriod:         defaultGracePeriod,
+		bundleExpiryWarning: defaultBundleExpiryWarning,
 	}
 	connection(c)
-	if err := c.init(opts); err != nil {
+	if err := c.init(ctx, opts); err != nil {
 		return nil, fmt.Errorf("failed to create client: %w", err)
 	}
 	return c, nil
@@ -68,35 +105,48 @@ func NewStaticTokenClient(token string, connection StaticTokenConnectConfig, opt
 // Stargate table auth API service URL, username, and password to obtain an auth
 // token for requests.
 func NewTableBasedTokenClient(astraURI, authServiceURI, username, password string, opts ...ClientOption) (*Client, error) {
+	return NewTableBasedTokenClientContext(context.Background(), astraURI, authServiceURI, username, password, opts...)
+}
+
+// NewTableBasedTokenClientContext is like NewTableBasedTokenClient, but uses
+// the provided context for the initial connection.
+func NewTableBasedTokenClientContext(ctx context.Context, astraURI, authServiceURI, username, password string, opts ...ClientOption) (*Client, error) {
 	c := &Client{
-		astraURI:       astraURI,
-		authServiceURL: authServiceURI,
-		authUsername:   username,
-		authPassword:   password,
-		deadline:       defaultDeadline,
-		timeout:        defaultTimeout,
-	}
-	if err := c.init(opts); err != nil {
+		astraURI:            astraURI,
+		tokenProvider:       NewTableBasedTokenProvider(authServiceURI, username, password),
+		deadline:            defaultDeadline,
+		timeout:             defaultTimeout,
+		gracePeriod:         defaultGracePeriod,
+		bundleExpiryWarning: defaultBundleExpiryWarning,
+	}
+	if err := c.init(ctx, opts); err != nil {
 		return nil, fmt.Errorf("failed to create client: %w", err)
 	}
 	return c, nil
 }
 
-func (c *Client) init(opts []ClientOption) error {
+func (c *Client) init(ctx context.Context, opts []ClientOption) error {
 	for _, opt := range opts {
 		opt(c)
 	}
 
-	if c.scbPath != "" {
+	if c.loadBundle != nil {
 		if c.tlsConfig != nil {
 			return fmt.Errorf("cannot specify both bundle and TLS config")
 		}
-		bundle, err := loadBundleZipFromPath(c.scbPath)
+		if c.bundleWatch > 0 && c.bundleFixed {
+			return fmt.Errorf("cannot watch bundle not specified by path")
+		}
+		bundle, err := c.loadBundle()
 		if err != nil {
 			return fmt.Errorf("failed to load secure connect bundle: %w", err)
 		}
-		c.astraURI = bundle.host
-		c.tlsConfig = bundle.tlsConfig
+		c.checkBundleExpiry(bundle)
+		c.astraURI = bundle.Addr()
+		c.tlsConfig = bundle.TLSConfig
+		c.bundleCreds = newReloadingCredentials(bundle.TLSConfig)
+	} else if c.bundleWatch > 0 {
+		return fmt.Errorf("cannot watch bundle without specifying one")
 	}
 
 	dialOpts := []grpc.DialOption{
@@ -104,7 +154,9 @@ func (c *Client) init(opts []ClientOption) error {
 	}
 
 	useTLS := c.tlsConfig != nil
-	if useTLS {
+	if c.bundleCreds != nil {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(c.bundleCreds))
+	} else if useTLS {
 		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(c.tlsConfig)))
 	} else if c.insecure {
 		log.Printf("WARNING: Using insecure gRPC connection. Do not do this in production.")
@@ -114,39 +166,80 @@ func (c *Client) init(opts []ClientOption) error {
 		dialOpts = append(dialOpts, defaultInsecureCredentials)
 	}
 
-	var creds credentials.PerRPCCredentials
-	if c.authServiceURL != "" {
-		if useTLS {
-			creds = auth.NewTableBasedTokenProvider(c.authServiceURL, c.authUsername, c.authPassword)
-		} else {
-			creds = auth.NewTableBasedTokenProviderUnsafe(c.authServiceURL, c.authUsername, c.authPassword)
-		}
-	} else {
-		if useTLS {
-			creds = auth.NewStaticTokenProvider(c.token)
-		} else {
-			creds = auth.NewStaticTokenProviderUnsafe(c.token)
-		}
-	}
-	dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(creds))
-
 	if c.grpcConnParams != nil {
 		dialOpts = append(dialOpts, grpc.WithConnectParams(*c.grpcConnParams))
 	}
 
-	conn, err := grpc.Dial(c.astraURI, dialOpts...)
+	dialCtx, cancel := context.WithTimeout(ctx, c.deadline)
+	defer cancel()
+
+	conn, err := grpc.DialContext(dialCtx, c.astraURI, dialOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to dial %q: %v", c.astraURI, err)
 	}
-
-	c.sgClient, err = client.NewStargateClientWithConn(conn, client.WithTimeout(c.deadline))
-	if err != nil {
-		return fmt.Errorf("failed to create stargate client: %v", err)
+	c.conn = conn
+	c.sgClient = pb.NewStargateClient(conn)
+	c.done = make(chan struct{})
+	if c.bundleWatch > 0 {
+		go c.watchBundle(c.bundleWatch)
 	}
 
 	return nil
 }
 
+// Close closes the client connection. Queries in flight are given up to the
+// shutdown grace period to complete, after which they are canceled. Queries
+// executed after Close is called return ErrClientClosed.
+//
+// Close is safe to call concurrently and more than once; all calls return
+// after the connection is closed.
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.closed = true
+		c.mu.Unlock()
+		if c.done != nil {
+			close(c.done)
+		}
+
+		done := make(chan struct{})
+		go func() {
+			c.inFlight.Wait()
+			close(done)
+		}()
+
+		t := time.NewTimer(c.gracePeriod)
+		defer t.Stop()
+		select {
+		case <-done:
+		case <-t.C:
+			log.Printf("WARNING: Canceling queries still in flight after %v shutdown grace period", c.gracePeriod)
+		}
+
+		if c.conn != nil {
+			c.closeErr = c.conn.Close()
+		}
+	})
+	return c.closeErr
+}
+
+// acquire registers a query in flight, or returns ErrClientClosed if the
+// client has been closed. Each successful call must be paired with a call to
+// release.
+func (c *Client) acquire() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return ErrClientClosed
+	}
+	c.inFlight.Add(1)
+	return nil
+}
+
+func (c *Client) release() {
+	c.inFlight.Done()
+}
+
 // Query creates a new Astra query.
 func (c *Client) Query(cql string, values ...any) *Query {
 	return &Query{
@@ -165,69 +258,163 @@ func (c *Client) Batch(queries ...*Query) *BatchQuery {
 	}
 }
 
-func (c *Client) execQuery(query *Query) (Rows, error) {
+func (c *Client) execQuery(ctx context.Context, query *Query) (Rows, error) {
+	res, err := c.execQueryPage(ctx, query, query.pagingState)
+	if err != nil {
+		return nil, err
+	}
+	rows := res.Rows
+	for len(res.PagingState) > 0 {
+		res, err = c.execQueryPage(ctx, query, res.PagingState)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, res.Rows...)
+	}
+	return rows, nil
+}
+
+// execQueryPage executes the query starting from the page identified by
+// pagingState, and returns the result for that page.
+func (c *Client) execQueryPage(ctx context.Context, query *Query, pagingState []byte) (*Result, error) {
 	q, err := query.toQueryProto()
 	if err != nil {
 		return nil, err
 	}
 
-	dps := c.defaultQueryParams.params
-	if dps != nil {
-		if query.params == nil {
-			q.Parameters = dps.toQueryParamsProto()
-		} else {
-			if query.params.keyspace != "" {
-				q.Parameters.Keyspace = &wrapperspb.StringValue{Value: dps.keyspace}
+	p := c.requestParams(query.params)
+	qr, err := c.execute(ctx, p, func(ctx context.Context, p *params, opts ...grpc.CallOption) (*pb.Response, error) {
+		q.Parameters, err = p.toQueryParamsProto()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create query parameters: %w", err)
+		}
+		if len(pagingState) > 0 {
+			if q.Parameters == nil {
+				q.Parameters = &pb.QueryParameters{}
 			}
+			q.Parameters.PagingState = &wrapperspb.BytesValue{Value: pagingState}
 		}
+		return c.sgClient.ExecuteQuery(ctx, q, opts...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
-	defer cancel()
+	res, err := newResult(qr)
+	if err != nil {
+		return nil, err
+	}
+	if res.SchemaChange != nil {
+		c.Schema().schemaChanged(res.SchemaChange)
+	}
+	return res, nil
+}
 
-	qr, err := c.sgClient.ExecuteQueryWithContext(q, ctx)
+func (c *Client) execBatch(ctx context.Context, bq *BatchQuery) (*Result, error) {
+	b, err := bq.toProto()
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %v", err)
+		return nil, fmt.Errorf("failed to create batch query proto: %w", err)
 	}
 
-	switch r := qr.Result.(type) {
-	case *pb.Response_ResultSet:
-		res, err := newRowsFromResultSet(r.ResultSet)
+	p := c.requestParams(bq.params)
+	br, err := c.execute(ctx, p, func(ctx context.Context, p *params, opts ...grpc.CallOption) (*pb.Response, error) {
+		b.Parameters, err = p.toBatchParamsProto()
 		if err != nil {
-			return nil, fmt.Errorf("failed to create rows from result set: %v", err)
+			return nil, fmt.Errorf("failed to create batch query parameters: %w", err)
 		}
-		return res, nil
-	case nil, *pb.Response_SchemaChange:
-		return nil, nil
+		return c.sgClient.ExecuteBatch(ctx, b, opts...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute batch query: %w", err)
 	}
 
-	return nil, fmt.Errorf("unexpected response type: %T, %v", qr.Result, qr.Result)
+	return newResult(br)
 }
 
-func (c *Client) execBatch(bq *BatchQuery) error {
-	b, err := bq.toProto()
-	if err != nil {
-		return fmt.Errorf("failed to create batch query proto: %w", err)
+// requestParams returns the parameters for a request with parameters p, with
+// the client defaults applied and, unless p sets one, a timestamp from the
+// client timestamp generator.
+func (c *Client) requestParams(p *params) *params {
+	p = p.withDefaults(c.defaultQueryParams.params)
+	if c.timestampGenerator == nil || (p != nil && p.timestamp != nil) {
+		return p
+	}
+	var res params
+	if p != nil {
+		res = *p
+	}
+	ts := c.timestampGenerator.Next()
+	res.timestamp = &ts
+	return &res
+}
+
+// stargateRequest performs a single Stargate request with the given
+// parameters.
+type stargateRequest func(ctx context.Context, p *params, opts ...grpc.CallOption) (*pb.Response, error)
+
+// execute performs req with parameters p. Failed requests are retried as
+// decided by the retry policy in p, if p marks the request as idempotent.
+// Errors returned by the server are converted to a *QueryError.
+func (c *Client) execute(ctx context.Context, p *params, req stargateRequest) (*pb.Response, error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
 	}
+	defer c.release()
 
-	dps := c.defaultQueryParams.params
-	if dps != nil {
-		if bq.params == nil {
-			b.Parameters = dps.toBatchParamsProto()
-		} else {
-			if bq.params.keyspace != "" {
-				b.Parameters.Keyspace = &wrapperspb.StringValue{Value: dps.keyspace}
-			}
+	for attempt := 1; ; attempt++ {
+		resp, err := c.authenticatedAttempt(ctx, p, req)
+		if err == nil {
+			return resp, nil
+		}
+
+		var qerr *QueryError
+		if !errors.As(err, &qerr) {
+			return nil, err
+		}
+		qerr.Attempts = attempt
+		if p == nil || !p.idempotent || p.retryPolicy == nil {
+			return nil, err
+		}
+
+		d := p.retryPolicy.Retry(RetryInfo{
+			Attempts:    attempt,
+			Err:         err,
+			Consistency: p.consistency,
+		})
+		if !d.Retry {
+			return nil, err
+		}
+		if d.Consistency != 0 && d.Consistency != p.consistency {
+			retryParams := *p
+			retryParams.consistency = d.Consistency
+			p = &retryParams
+		}
+
+		t := time.NewTimer(d.Delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
 		}
 	}
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+// attempt performs req once within the client query timeout.
+func (c *Client) attempt(ctx context.Context, p *params, req stargateRequest) (*pb.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
-	_, err = c.sgClient.ExecuteBatchWithContext(b, ctx)
+	var trailer metadata.MD
+	resp, err := req(ctx, p, grpc.Trailer(&trailer))
 	if err != nil {
-		return fmt.Errorf("failed to execute batch query: %w", err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if _, ok := status.FromError(err); !ok {
+			return nil, err
+		}
+		return nil, newQueryError(err, trailer)
 	}
-
-	return nil
+	return resp, nil
 }
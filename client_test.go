@@ -1,10 +1,17 @@
 package astra
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"sync"
+	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 func ExampleNewStaticTokenClient() {
@@ -18,6 +25,7 @@ func ExampleNewStaticTokenClient() {
 	if err != nil {
 		log.Fatalf("failed to initialize client: %v", err)
 	}
+	defer c.Close()
 
 	_, err = c.Query(`<some query>`).Exec()
 	if err != nil {
@@ -34,6 +42,26 @@ func ExampleNewStaticTokenClient_withSecureConnectBundle() {
 	if err != nil {
 		log.Fatalf("failed to initialize client: %v", err)
 	}
+	defer c.Close()
+
+	_, err = c.Query(`<some query>`).Exec()
+	if err != nil {
+		log.Fatalf("failed to execute query: %v", err)
+	}
+}
+
+func ExampleNewStaticTokenClient_withSecureConnectBundleEnv() {
+	// ASTRA_SCB holds the base64-encoded bundle, e.g. from a Kubernetes
+	// secret.
+	c, err := NewStaticTokenClient(token,
+		WithSecureConnectBundleEnv("ASTRA_SCB"),
+		WithDefaultKeyspace("example"),
+		// other options
+	)
+	if err != nil {
+		log.Fatalf("failed to initialize client: %v", err)
+	}
+	defer c.Close()
 
 	_, err = c.Query(`<some query>`).Exec()
 	if err != nil {
@@ -53,6 +81,7 @@ func ExampleNewTableBasedTokenClient() {
 	if err != nil {
 		log.Fatalf("failed to initialize client: %v", err)
 	}
+	defer c.Close()
 
 	_, err = c.Query(`<some query>`).Exec()
 	if err != nil {
@@ -151,6 +180,43 @@ func ExampleClient_Query_scan() {
 	// &{ID:12345678-1234-5678-1234-567812345678 Name:Alice Age:30}
 }
 
+func ExampleScanAll() {
+	c, err := NewStaticTokenClient(
+		token, WithAstraURI(endpoint),
+		WithDefaultKeyspace("example"),
+	)
+	if err != nil {
+		log.Fatalf("failed to initialize client: %v", err)
+	}
+
+	rows, err := c.Query(
+		`SELECT id, name, age 
+		 FROM users 
+		 WHERE id = ?`,
+		uuid.MustParse("12345678-1234-5678-1234-567812345678"),
+	).Exec()
+	if err != nil {
+		log.Fatalf("failed to execute query: %v", err)
+	}
+
+	type User struct {
+		ID   uuid.UUID
+		Name string
+		Age  int16 `cql:"age"`
+	}
+
+	users, err := ScanAll[User](rows)
+	if err != nil {
+		log.Fatalf("failed to scan rows: %v", err)
+	}
+	for _, u := range users {
+		fmt.Printf("%+v\n", u)
+	}
+
+	// Output:
+	// {ID:12345678-1234-5678-1234-567812345678 Name:Alice Age:30}
+}
+
 func ExampleClient_Batch() {
 	c, err := NewStaticTokenClient(
 		token, WithAstraURI(endpoint),
@@ -214,3 +280,92 @@ func ExampleClient_Batch_withOptions() {
 	// Output:
 	// rows returned: 3
 }
+
+// newUnconnectedTestClient returns a Client whose connection is never
+// established, for testing client behavior that does not reach the server.
+func newUnconnectedTestClient(t *testing.T) *Client {
+	t.Helper()
+	conn, err := grpc.Dial("localhost:0", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to create connection: %v", err)
+	}
+	return &Client{
+		conn:        conn,
+		sgClient:    pb.NewStargateClient(conn),
+		timeout:     defaultTimeout,
+		gracePeriod: defaultGracePeriod,
+	}
+}
+
+func TestClient_Close(t *testing.T) {
+	c := newUnconnectedTestClient(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.Close(); err != nil {
+				t.Errorf("Close() unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if _, err := c.Query(`SELECT * FROM users`).Exec(); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Query.Exec() after Close() got error %v, want %v", err, ErrClientClosed)
+	}
+	if err := c.Batch(c.Query(`DELETE FROM users`)).Exec(); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("BatchQuery.Exec() after Close() got error %v, want %v", err, ErrClientClosed)
+	}
+	if it := c.Query(`SELECT * FROM users`).Iter(); it.Next() || !errors.Is(it.Err(), ErrClientClosed) {
+		t.Errorf("Iter.Err() after Close() got error %v, want %v", it.Err(), ErrClientClosed)
+	}
+}
+
+func TestClient_Close_waitsForInFlight(t *testing.T) {
+	c := newUnconnectedTestClient(t)
+	if err := c.acquire(); err != nil {
+		t.Fatalf("acquire() unexpected error: %v", err)
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		_ = c.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+		t.Fatalf("Close() returned with a query in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	c.release()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatalf("Close() did not return after in-flight query completed")
+	}
+}
+
+func TestClient_Close_gracePeriod(t *testing.T) {
+	c := newUnconnectedTestClient(t)
+	c.gracePeriod = 10 * time.Millisecond
+	if err := c.acquire(); err != nil {
+		t.Fatalf("acquire() unexpected error: %v", err)
+	}
+	defer c.release()
+
+	closed := make(chan struct{})
+	go func() {
+		_ = c.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatalf("Close() did not return after grace period elapsed")
+	}
+}
@@ -0,0 +1,79 @@
+package astra
+
+import (
+	"fmt"
+
+	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
+)
+
+// Consistency is the consistency level with which to execute a query. The zero
+// value uses the client default consistency, if set, or the server default
+// otherwise.
+// See https://docs.datastax.com/en/cassandra-oss/3.x/cassandra/dml/dmlConfigConsistency.html
+type Consistency uint8
+
+// Consistency levels for Query and BatchQuery.
+const (
+	ConsistencyAny Consistency = iota + 1
+	ConsistencyOne
+	ConsistencyTwo
+	ConsistencyThree
+	ConsistencyQuorum
+	ConsistencyAll
+	ConsistencyLocalQuorum
+	ConsistencyEachQuorum
+	ConsistencySerial
+	ConsistencyLocalSerial
+	ConsistencyLocalOne
+)
+
+var consistencyProtos = map[Consistency]pb.Consistency{
+	ConsistencyAny:         pb.Consistency_ANY,
+	ConsistencyOne:         pb.Consistency_ONE,
+	ConsistencyTwo:         pb.Consistency_TWO,
+	ConsistencyThree:       pb.Consistency_THREE,
+	ConsistencyQuorum:      pb.Consistency_QUORUM,
+	ConsistencyAll:         pb.Consistency_ALL,
+	ConsistencyLocalQuorum: pb.Consistency_LOCAL_QUORUM,
+	ConsistencyEachQuorum:  pb.Consistency_EACH_QUORUM,
+	ConsistencySerial:      pb.Consistency_SERIAL,
+	ConsistencyLocalSerial: pb.Consistency_LOCAL_SERIAL,
+	ConsistencyLocalOne:    pb.Consistency_LOCAL_ONE,
+}
+
+// String returns the CQL name of the consistency level, e.g. "LOCAL_QUORUM".
+func (c Consistency) String() string {
+	if c == 0 {
+		return "DEFAULT"
+	}
+	if p, ok := consistencyProtos[c]; ok {
+		return p.String()
+	}
+	return fmt.Sprintf("Consistency(%d)", uint8(c))
+}
+
+// isSerial reports whether c is a serial consistency level, for the
+// conditional update phase of lightweight transactions.
+func (c Consistency) isSerial() bool {
+	return c == ConsistencySerial || c == ConsistencyLocalSerial
+}
+
+func (c Consistency) toProto() (*pb.ConsistencyValue, error) {
+	if c == 0 {
+		return nil, nil
+	}
+	p, ok := consistencyProtos[c]
+	if !ok {
+		return nil, fmt.Errorf("unknown consistency: %v", c)
+	}
+	return &pb.ConsistencyValue{Value: p}, nil
+}
+
+func consistencyFromProto(p pb.Consistency) Consistency {
+	for c, cp := range consistencyProtos {
+		if cp == p {
+			return c
+		}
+	}
+	return 0
+}
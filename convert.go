@@ -250,16 +250,91 @@ func convertAssign(dest, src any) error {
 			dv.Set(reflect.Zero(dv.Type()))
 			return nil
 		}
-	case reflect.Array, reflect.Slice:
+		if sv.Kind() == reflect.Map {
+			return convertAssignMap(dv, sv)
+		}
+	case reflect.Slice:
 		if src == nil {
 			dv.Set(reflect.Zero(dv.Type()))
 			return nil
 		}
+		// Byte slices are blobs, not lists.
+		if sv.Kind() == reflect.Slice && sv.Type().Elem().Kind() != reflect.Uint8 {
+			return convertAssignSlice(dv, sv)
+		}
+	case reflect.Array:
+		if src == nil {
+			dv.Set(reflect.Zero(dv.Type()))
+			return nil
+		}
+	case reflect.Struct:
+		if src == nil {
+			dv.Set(reflect.Zero(dv.Type()))
+			return nil
+		}
+		if m, ok := src.(map[string]any); ok {
+			return convertAssignUDT(dv, m)
+		}
 	}
 
 	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", src, dest)
 }
 
+// convertAssignMap converts each key and value of map sv into the key and value
+// types of map dv.
+func convertAssignMap(dv, sv reflect.Value) error {
+	m := reflect.MakeMapWithSize(dv.Type(), sv.Len())
+	iter := sv.MapRange()
+	for iter.Next() {
+		k := reflect.New(dv.Type().Key())
+		if err := convertAssign(k.Interface(), iter.Key().Interface()); err != nil {
+			return fmt.Errorf("converting map key: %w", err)
+		}
+		v := reflect.New(dv.Type().Elem())
+		if err := convertAssign(v.Interface(), iter.Value().Interface()); err != nil {
+			return fmt.Errorf("converting map value: %w", err)
+		}
+		m.SetMapIndex(k.Elem(), v.Elem())
+	}
+	dv.Set(m)
+	return nil
+}
+
+// convertAssignSlice converts each element of slice sv into the element type
+// of slice dv.
+func convertAssignSlice(dv, sv reflect.Value) error {
+	l := sv.Len()
+	s := reflect.MakeSlice(dv.Type(), l, l)
+	for i := 0; i < l; i++ {
+		if err := convertAssign(s.Index(i).Addr().Interface(), sv.Index(i).Interface()); err != nil {
+			return fmt.Errorf("converting slice element %d: %w", i, err)
+		}
+	}
+	dv.Set(s)
+	return nil
+}
+
+// convertAssignUDT assigns the fields of the UDT src to the fields of struct
+// dv, as mapped by cqlFields. UDT fields with no matching struct field are
+// ignored, and NULL UDT fields are assigned the zero value.
+func convertAssignUDT(dv reflect.Value, src map[string]any) error {
+	for _, f := range cqlFields(dv.Type()) {
+		v, ok := src[f.name]
+		if !ok {
+			continue
+		}
+		fv := fieldByIndexAlloc(dv, f.index)
+		if v == nil {
+			fv.Set(reflect.Zero(fv.Type()))
+			continue
+		}
+		if err := convertAssign(fv.Addr().Interface(), v); err != nil {
+			return fmt.Errorf("converting UDT field %q: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
 func strconvErr(err error) error {
 	if ne, ok := err.(*strconv.NumError); ok {
 		return ne.Err
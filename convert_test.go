@@ -291,3 +291,55 @@ func TestUserDefinedBytes(t *testing.T) {
 		t.Fatal("userDefinedBytes got potentially dirty driver memory")
 	}
 }
+
+func TestConvertAssign_udt(t *testing.T) {
+	type address struct {
+		Street string `cql:"street"`
+		Zip    int    `cql:"zip"`
+	}
+	udt := map[string]any{"street": "Main St", "zip": int64(12345), "country": "US"}
+
+	var a address
+	if err := convertAssign(&a, udt); err != nil {
+		t.Fatalf("convertAssign(%T, %v) unexpected error: %v", &a, udt, err)
+	}
+	if want := (address{Street: "Main St", Zip: 12345}); a != want {
+		t.Errorf("convertAssign(%T, %v) got %+v, want %+v", &a, udt, a, want)
+	}
+
+	var ap *address
+	if err := convertAssign(&ap, map[string]any{"street": "Main St", "zip": nil}); err != nil {
+		t.Fatalf("convertAssign(%T) unexpected error: %v", &ap, err)
+	}
+	if want := (address{Street: "Main St"}); ap == nil || *ap != want {
+		t.Errorf("convertAssign(%T) got %+v, want %+v", &ap, ap, want)
+	}
+
+	var as []address
+	if err := convertAssign(&as, []map[string]any{udt}); err != nil {
+		t.Fatalf("convertAssign(%T) unexpected error: %v", &as, err)
+	}
+	if want := []address{{Street: "Main St", Zip: 12345}}; !reflect.DeepEqual(as, want) {
+		t.Errorf("convertAssign(%T) got %+v, want %+v", &as, as, want)
+	}
+
+	var am map[string]address
+	if err := convertAssign(&am, map[string]map[string]any{"home": udt}); err != nil {
+		t.Fatalf("convertAssign(%T) unexpected error: %v", &am, err)
+	}
+	if want := map[string]address{"home": {Street: "Main St", Zip: 12345}}; !reflect.DeepEqual(am, want) {
+		t.Errorf("convertAssign(%T) got %+v, want %+v", &am, am, want)
+	}
+
+	a = address{Street: "Main St"}
+	if err := convertAssign(&a, nil); err != nil {
+		t.Fatalf("convertAssign(%T, nil) unexpected error: %v", &a, err)
+	}
+	if a != (address{}) {
+		t.Errorf("convertAssign(%T, nil) got %+v, want zero value", &a, a)
+	}
+
+	if err := convertAssign(&a, map[string]any{"zip": "foo"}); err == nil {
+		t.Errorf("convertAssign(%T) with unassignable field got nil error, want error", &a)
+	}
+}
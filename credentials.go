@@ -0,0 +1,294 @@
+package astra
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnvApplicationToken is the environment variable conventionally holding an
+// Astra DB application token.
+const EnvApplicationToken = "ASTRA_DB_APPLICATION_TOKEN"
+
+const (
+	defaultExecCredentialsTTL     = 5 * time.Minute
+	defaultExecCredentialsTimeout = 30 * time.Second
+)
+
+// Credentials are the credentials with which a client authenticates.
+type Credentials struct {
+	// Token is the auth token sent with requests.
+	Token string
+	// Expiry is the time at which the token expires, or the zero time if
+	// unknown.
+	Expiry time.Time
+}
+
+// CredentialsSource is a source of credentials, such as environment
+// variables, a file or an external command, so that they need not be part of
+// the client configuration. Use WithCredentialsSource to install one on a
+// Client.
+//
+// Credentials is called for each request, so implementations should cache
+// credentials which are expensive to obtain. A source may also implement
+// Invalidate(token string), which is called as by TokenProvider.Invalidate.
+// Implementations must be safe for concurrent use.
+type CredentialsSource interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// sourceTokenProvider provides the tokens of a CredentialsSource.
+type sourceTokenProvider struct {
+	source CredentialsSource
+}
+
+func (p sourceTokenProvider) Token(ctx context.Context) (string, error) {
+	creds, err := p.source.Credentials(ctx)
+	if err != nil {
+		return "", err
+	}
+	if creds.Token == "" {
+		return "", fmt.Errorf("credentials source returned no token")
+	}
+	return creds.Token, nil
+}
+
+func (p sourceTokenProvider) Invalidate(token string) {
+	if inv, ok := p.source.(interface{ Invalidate(string) }); ok {
+		inv.Invalidate(token)
+	}
+}
+
+// EnvCredentialsSource reads the token from the first set of a list of
+// environment variables. Variables are read for each request, so changes to
+// them take effect immediately.
+type EnvCredentialsSource struct {
+	// Names are the names of the environment variables, in order of
+	// precedence. Defaults to EnvApplicationToken.
+	Names []string
+}
+
+// NewEnvCredentialsSource creates a new EnvCredentialsSource reading the
+// named environment variables, or EnvApplicationToken if none are named.
+func NewEnvCredentialsSource(names ...string) *EnvCredentialsSource {
+	return &EnvCredentialsSource{Names: names}
+}
+
+// Credentials returns the token held by the first non-empty variable.
+func (s *EnvCredentialsSource) Credentials(context.Context) (Credentials, error) {
+	names := s.Names
+	if len(names) == 0 {
+		names = []string{EnvApplicationToken}
+	}
+	for _, name := range names {
+		if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+			return Credentials{Token: v}, nil
+		}
+	}
+	return Credentials{}, fmt.Errorf("none of the environment variables %s are set", strings.Join(names, ", "))
+}
+
+// FileCredentialsSource reads the token from a file, such as a mounted
+// Kubernetes secret, re-reading it whenever it changes. Surrounding
+// whitespace is ignored.
+type FileCredentialsSource struct {
+	// Path is the path of the file.
+	Path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	token   string
+}
+
+// NewFileCredentialsSource creates a new FileCredentialsSource reading the
+// file at path.
+func NewFileCredentialsSource(path string) *FileCredentialsSource {
+	return &FileCredentialsSource{Path: path}
+}
+
+// Credentials returns the token in the file, reading it if it has changed
+// since it was last read.
+func (s *FileCredentialsSource) Credentials(context.Context) (Credentials, error) {
+	// Stat follows symlinks, so that the atomic updates of Kubernetes
+	// projected volumes, which swap a symlink, are seen as changes.
+	fi, err := os.Stat(s.Path)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && fi.ModTime().Equal(s.modTime) && fi.Size() == s.size {
+		return Credentials{Token: s.token}, nil
+	}
+	b, err := os.ReadFile(s.Path)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to read token file: %w", err)
+	}
+	token := string(bytes.TrimSpace(b))
+	if token == "" {
+		return Credentials{}, fmt.Errorf("token file %s is empty", s.Path)
+	}
+	s.token, s.modTime, s.size = token, fi.ModTime(), fi.Size()
+	return Credentials{Token: token}, nil
+}
+
+// ExecCredentialsSource obtains the token by running a helper command, such
+// as a secret manager CLI. The command prints either the token, or a JSON
+// object with the token and, optionally, its RFC 3339 expiry time:
+//
+//	{"token": "AstraCS:...", "expiry": "2022-06-01T12:00:00Z"}
+//
+// The token is cached until it expires, for TTL if it has no expiry, or
+// until it is rejected by the server. Concurrent requests for a token share a
+// single run of the command.
+type ExecCredentialsSource struct {
+	// Command is the name or path of the command, and Args its arguments.
+	Command string
+	Args    []string
+	// TTL is how long to cache tokens without an expiry. Defaults to 5
+	// minutes.
+	TTL time.Duration
+	// Timeout is how long the command may run before it is killed. Defaults
+	// to 30 seconds.
+	Timeout time.Duration
+
+	mu      sync.Mutex
+	creds   Credentials
+	expires time.Time
+	running chan struct{}
+	err     error
+
+	now func() time.Time
+}
+
+// NewExecCredentialsSource creates a new ExecCredentialsSource running
+// command with args.
+func NewExecCredentialsSource(command string, args ...string) *ExecCredentialsSource {
+	return &ExecCredentialsSource{Command: command, Args: args}
+}
+
+// Credentials returns the cached token, running the command if it has
+// expired or been invalidated.
+func (s *ExecCredentialsSource) Credentials(ctx context.Context) (Credentials, error) {
+	s.mu.Lock()
+	if s.creds.Token != "" && s.clock().Before(s.expires) {
+		creds := s.creds
+		s.mu.Unlock()
+		return creds, nil
+	}
+
+	wait := s.running
+	if wait == nil {
+		wait = make(chan struct{})
+		s.running = wait
+		// Run the command in the background so that other callers waiting
+		// for the token are unaffected if ctx is canceled.
+		go s.run(wait)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return Credentials{}, ctx.Err()
+	case <-wait:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.creds.Token == "" {
+		return Credentials{}, s.err
+	}
+	return s.creds, nil
+}
+
+// run runs the command, caches the token it prints and closes done.
+func (s *ExecCredentialsSource) run(done chan struct{}) {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = defaultExecCredentialsTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	creds, err := s.exec(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.creds, s.err = Credentials{}, err
+	} else {
+		ttl := s.TTL
+		if ttl <= 0 {
+			ttl = defaultExecCredentialsTTL
+		}
+		s.creds, s.err = creds, nil
+		s.expires = s.clock().Add(ttl)
+		if !creds.Expiry.IsZero() {
+			s.expires = creds.Expiry
+		}
+	}
+	s.running = nil
+	close(done)
+}
+
+// exec runs the command and parses its output.
+func (s *ExecCredentialsSource) exec(ctx context.Context) (Credentials, error) {
+	cmd := exec.CommandContext(ctx, s.Command, s.Args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to run credentials command %s: %w: %s", s.Command, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	creds, err := parseExecCredentials(out)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to parse output of credentials command %s: %w", s.Command, err)
+	}
+	return creds, nil
+}
+
+// Invalidate discards token if it is the cached token, so that the command
+// is run again by the next call to Credentials.
+func (s *ExecCredentialsSource) Invalidate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.creds.Token == token {
+		s.creds = Credentials{}
+	}
+}
+
+func (s *ExecCredentialsSource) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// parseExecCredentials parses the output of a credentials command.
+func parseExecCredentials(out []byte) (Credentials, error) {
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return Credentials{}, fmt.Errorf("no token")
+	}
+	if out[0] != '{' {
+		return Credentials{Token: string(out)}, nil
+	}
+	var v struct {
+		Token  string    `json:"token"`
+		Expiry time.Time `json:"expiry"`
+	}
+	if err := json.Unmarshal(out, &v); err != nil {
+		return Credentials{}, err
+	}
+	if v.Token == "" {
+		return Credentials{}, fmt.Errorf("no token")
+	}
+	return Credentials{Token: v.Token, Expiry: v.Expiry}, nil
+}
@@ -0,0 +1,191 @@
+package astra
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEnvCredentialsSource(t *testing.T) {
+	t.Setenv(EnvApplicationToken, "")
+	t.Setenv("ASTRA_GO_SDK_TEST_TOKEN", " AstraCS:test\n")
+
+	s := NewEnvCredentialsSource()
+	if _, err := s.Credentials(context.Background()); err == nil {
+		t.Errorf("Credentials() got nil error with no variables set, want error")
+	}
+
+	s = NewEnvCredentialsSource(EnvApplicationToken, "ASTRA_GO_SDK_TEST_TOKEN")
+	got, err := s.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("Credentials() unexpected error: %v", err)
+	}
+	if want := "AstraCS:test"; got.Token != want {
+		t.Errorf("Credentials() got token %q, want %q", got.Token, want)
+	}
+}
+
+func TestFileCredentialsSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	write := func(token string, modTime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(token), 0o600); err != nil {
+			t.Fatalf("failed to write token file: %v", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("failed to change token file times: %v", err)
+		}
+	}
+	token := func(s *FileCredentialsSource) string {
+		t.Helper()
+		got, err := s.Credentials(context.Background())
+		if err != nil {
+			t.Fatalf("Credentials() unexpected error: %v", err)
+		}
+		return got.Token
+	}
+
+	s := NewFileCredentialsSource(path)
+	if _, err := s.Credentials(context.Background()); err == nil {
+		t.Errorf("Credentials() got nil error for missing file, want error")
+	}
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	write("token-1\n", start)
+	if got := token(s); got != "token-1" {
+		t.Errorf("Credentials() got token %q, want %q", got, "token-1")
+	}
+	write("token-2\n", start.Add(time.Minute))
+	if got := token(s); got != "token-2" {
+		t.Errorf("Credentials() after change got token %q, want %q", got, "token-2")
+	}
+}
+
+func TestExecCredentialsSource(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "runs")
+
+	// The helper appends to counter on each run and prints its output.
+	helper := func(output string) *ExecCredentialsSource {
+		return NewExecCredentialsSource("sh", "-c", `echo run >> "$0"; printf '%s' "$1"`, counter, output)
+	}
+	runs := func() int {
+		b, _ := os.ReadFile(counter)
+		return len(b) / len("run\n")
+	}
+
+	now := time.Now()
+	s := helper("AstraCS:plain\n")
+	s.now = func() time.Time { return now }
+	for i := 0; i < 2; i++ {
+		got, err := s.Credentials(context.Background())
+		if err != nil {
+			t.Fatalf("Credentials() unexpected error: %v", err)
+		}
+		if got.Token != "AstraCS:plain" {
+			t.Errorf("Credentials() got token %q, want %q", got.Token, "AstraCS:plain")
+		}
+	}
+	if got := runs(); got != 1 {
+		t.Errorf("got %d runs of cached command, want 1", got)
+	}
+	s.Invalidate("AstraCS:plain")
+	if _, err := s.Credentials(context.Background()); err != nil {
+		t.Fatalf("Credentials() unexpected error: %v", err)
+	}
+	if got := runs(); got != 2 {
+		t.Errorf("got %d runs after invalidation, want 2", got)
+	}
+	now = now.Add(defaultExecCredentialsTTL)
+	if _, err := s.Credentials(context.Background()); err != nil {
+		t.Fatalf("Credentials() unexpected error: %v", err)
+	}
+	if got := runs(); got != 3 {
+		t.Errorf("got %d runs after TTL, want 3", got)
+	}
+
+	s = helper(`{"token": "AstraCS:json", "expiry": "2030-01-02T03:04:05Z"}`)
+	got, err := s.Credentials(context.Background())
+	if err != nil {
+		t.Fatalf("Credentials() unexpected error: %v", err)
+	}
+	want := Credentials{Token: "AstraCS:json", Expiry: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Credentials() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := NewExecCredentialsSource("sh", "-c", "exit 1").Credentials(context.Background()); err == nil {
+		t.Errorf("Credentials() got nil error for failing command, want error")
+	}
+	if _, err := helper("").Credentials(context.Background()); err == nil {
+		t.Errorf("Credentials() got nil error for empty output, want error")
+	}
+}
+
+func TestExecCredentialsSource_concurrent(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	counter := filepath.Join(t.TempDir(), "runs")
+	s := NewExecCredentialsSource("sh", "-c", `echo run >> "$0"; sleep 0.2; echo AstraCS:slow`, counter)
+
+	// A caller whose context is canceled stops waiting, without affecting
+	// the other callers.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.Credentials(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Credentials() with expired context got error %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// Concurrent callers share a single run of the command.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := s.Credentials(context.Background())
+			if err != nil || got.Token != "AstraCS:slow" {
+				t.Errorf("Credentials() got (%q, %v), want %q", got.Token, err, "AstraCS:slow")
+			}
+		}()
+	}
+	wg.Wait()
+	if b, _ := os.ReadFile(counter); len(b) != len("run\n") {
+		t.Errorf("got %d runs of command, want 1", len(b)/len("run\n"))
+	}
+
+	// Commands are killed after the timeout.
+	s = NewExecCredentialsSource("sh", "-c", "exec sleep 10")
+	s.Timeout = 50 * time.Millisecond
+	start := time.Now()
+	if _, err := s.Credentials(context.Background()); err == nil {
+		t.Errorf("Credentials() got nil error for command exceeding timeout, want error")
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("Credentials() returned after %v, want command killed after timeout", d)
+	}
+}
+
+func TestSourceTokenProvider(t *testing.T) {
+	t.Setenv("ASTRA_GO_SDK_TEST_TOKEN", "AstraCS:env")
+	fake := &fakeStargateClient{}
+	c := &Client{sgClient: fake, timeout: time.Second}
+	WithCredentialsSource(NewEnvCredentialsSource("ASTRA_GO_SDK_TEST_TOKEN"))(c)
+
+	if _, err := c.Query("SELECT * FROM t").Exec(); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"AstraCS:env"}, fake.tokens); diff != "" {
+		t.Errorf("sent tokens mismatch (-want +got):\n%s", diff)
+	}
+}
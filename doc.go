@@ -24,6 +24,35 @@
 //	    // Optional default keyspace in which to run queries that do not specify
 //	    // keyspace.
 //	    astra.WithDefaultKeyspace(keyspace),
+//	    // Optional default consistency level for queries that do not specify
+//	    // one.
+//	    astra.WithDefaultConsistency(astra.ConsistencyLocalQuorum),
+//	)
+//
+// To connect with a secure connect bundle instead of an Astra URI and TLS
+// config, pass WithSecureConnectBundle with the path of the bundle, or
+// WithSecureConnectBundleBytes, WithSecureConnectBundleReader or
+// WithSecureConnectBundleEnv where the bundle is not a file, e.g. when it is
+// provided as a secret. LoadBundle reads a bundle for inspection, and
+// ValidateBundle reports problems with a bundle such as a client certificate
+// not matching its key or not signed by the bundle CA. Bundles are validated
+// when creating a client, which fails if their certificates have expired, and
+// a warning is logged if they expire within the window set by
+// WithBundleExpiryWarning. To rotate certificates without recreating the
+// client, call Client.ReloadBundle or use WithBundleWatch to reload the bundle
+// periodically; new connections use the reloaded credentials while queries in
+// flight complete undisturbed.
+//
+// By default, the address dialed is derived from the bundle's config.json as
+// "<cluster ID>-<cluster region>.apps.astra.datastax.com:443". Pass
+// BundleOptions to the bundle option for private link endpoints, custom
+// domains or self-hosted Stargate.
+//
+//	astra.WithSecureConnectBundle(path, astra.BundleExactHost())
+//
+//	c, err := astra.NewStaticTokenClient(token,
+//	    // Environment variable holding the base64-encoded bundle.
+//	    astra.WithSecureConnectBundleEnv("ASTRA_SCB"),
 //	)
 //
 // Use the NewTableBasedTokenClient method to connect to Astra using a Stargate
@@ -40,6 +69,37 @@
 //	    ...
 //	)
 //
+// Tokens obtained with table-based authentication are cached and refreshed
+// before they expire. To control caching and refreshing, or to obtain tokens
+// some other way, pass a TokenProvider, such as a configured
+// TableBasedTokenProvider, with WithTokenProvider. Requests rejected as
+// unauthenticated are retried once with a new token.
+//
+//	c, err := astra.NewStaticTokenClient("", astra.WithAstraURI(astraURI),
+//	    astra.WithTokenProvider(&astra.TableBasedTokenProvider{
+//	        ServiceURL:    authServiceURL,
+//	        Username:      username,
+//	        Password:      password,
+//	        RefreshBefore: 10 * time.Minute,
+//	    }),
+//	)
+//
+// To keep tokens out of the client configuration, read them from a
+// CredentialsSource with WithCredentialsSource: EnvCredentialsSource reads
+// environment variables such as ASTRA_DB_APPLICATION_TOKEN,
+// FileCredentialsSource reads a file, such as a mounted Kubernetes secret,
+// whenever it changes, and ExecCredentialsSource runs a helper command.
+//
+//	c, err := astra.NewStaticTokenClient("", astra.WithAstraURI(astraURI),
+//	    astra.WithCredentialsSource(astra.NewFileCredentialsSource("/var/run/secrets/astra/token")),
+//	)
+//
+// Call Client.Close to release the connection once the client is no longer
+// needed. Queries in flight are given a grace period to complete, configurable
+// with WithShutdownGracePeriod.
+//
+//	defer c.Close()
+//
 // # Querying
 //
 // Create new queries by calling Client.Query to return a new Query, then
@@ -53,6 +113,18 @@
 //	    // Do something with row.
 //	}
 //
+// Use Query.ExecContext to bound or cancel the query with a context. The
+// client query timeout still applies.
+//
+//	rows, err := c.Query("SELECT * FROM table").ExecContext(ctx)
+//
+// Pass values for positional bind markers ("?") to Client.Query, or use named
+// bind markers (":name") and pass their values with Query.Bind.
+//
+//	rows, err := c.Query("SELECT * FROM users WHERE id = :id").
+//	    Bind(map[string]any{"id": id}).
+//	    Exec()
+//
 // Iterate over the returned Rows using a standard for loop. Call
 // Row.Values to inspect the values.
 //
@@ -62,6 +134,173 @@
 //	    someNumber := vals[1].(int64)
 //	}
 //
+// Use Row.Get to look up a value by column name, and Rows.Columns or
+// Row.Columns to inspect the names and CQL types of the returned columns.
+//
+//	for _, col := range rows.Columns() {
+//	    fmt.Printf("%s: %s\n", col.Name, col.Type) // e.g. "tags: set<text>"
+//	}
+//
+// Call Row.ScanStruct or ScanAll to copy row values into structs, matching
+// columns to fields by their `cql` tag or, if untagged, the snake case form of
+// the field name.
+//
+//	type User struct {
+//	    ID   uuid.UUID
+//	    Name string `cql:"username"`
+//	}
+//
+//	users, err := astra.ScanAll[User](rows)
+//
+// Call Client.Insert or Client.Update to write a struct as a row, or
+// Query.BindStruct to bind named markers from struct fields. Fields tagged
+// "omitempty" are left unset when zero, and fields tagged "key" identify the
+// row to update.
+//
+//	type User struct {
+//	    ID    uuid.UUID `cql:"id,key"`
+//	    Name  string    `cql:"username"`
+//	    Email string    `cql:"email,omitempty"`
+//	}
+//
+//	_, err := c.Insert("users", &u, astra.TTL(time.Hour)).Exec()
+//
+// # Errors
+//
+// Failures returned by the server are reported as a *QueryError, which
+// matches sentinel errors such as ErrUnavailable, ErrReadTimeout,
+// ErrWriteTimeout and ErrInvalidQuery with errors.Is, and carries details such
+// as the consistency level and replica counts of the failure.
+//
+//	_, err := c.Query("INSERT INTO table (id) VALUES (?)", id).Exec()
+//	var qerr *astra.QueryError
+//	if errors.Is(err, astra.ErrWriteTimeout) && errors.As(err, &qerr) {
+//	    log.Printf("%d of %d replicas acknowledged %s write", qerr.Received, qerr.Required, qerr.WriteType)
+//	}
+//
+// # Results
+//
+// Query.ExecResult executes a single page of a query and returns a Result
+// carrying, besides the rows, the server warnings, the schema change made by
+// a DDL statement, the trace, and the paging state of the following page.
+//
+//	res, err := c.Query("CREATE TABLE IF NOT EXISTS ks.users (id uuid PRIMARY KEY)").ExecResult()
+//	...
+//	for _, w := range res.Warnings {
+//	    log.Printf("server warning: %s", w)
+//	}
+//	if sc := res.SchemaChange; sc != nil {
+//	    log.Printf("%s %s %s.%s", sc.Type, sc.Target, sc.Keyspace, sc.Name)
+//	}
+//
+// # Lightweight transactions
+//
+// Conditional statements are executed with Query.ExecCAS, which reports
+// whether the statement was applied and, if not, scans the existing values
+// returned by the server. Query.MapExecCAS stores them in a map instead, and
+// BatchQuery.ExecCAS executes a batch of conditional statements. The serial
+// consistency level is set with SerialConsistency.
+//
+//	var owner string
+//	applied, err := c.Query("UPDATE locks SET owner = ? WHERE name = ? IF owner = null", me, name).
+//	    SerialConsistency(astra.ConsistencyLocalSerial).
+//	    ExecCAS(&owner)
+//
+// # Write timestamps
+//
+// By default, the server assigns write timestamps. Query.WithTimestamp and
+// BatchQuery.WithTimestamp set a client-side timestamp, and
+// WithTimestampGenerator installs a generator, e.g. a
+// MonotonicTimestampGenerator, used for all queries which do not set one.
+//
+//	_, err := c.Query("UPDATE users SET name = ? WHERE id = ?", name, id).
+//	    WithTimestamp(event.Time).
+//	    Exec()
+//
+// # Retries
+//
+// Queries and batches marked idempotent are retried according to a
+// RetryPolicy, set for all queries with WithRetryPolicy or per query with
+// Query.RetryPolicy. ExponentialBackoffRetryPolicy retries transient failures
+// with a jittered, exponentially increasing delay, and
+// DowngradingConsistencyRetryPolicy retries once at a lower consistency level.
+// The number of attempts made is reported by QueryError.Attempts.
+//
+//	c, err := astra.NewStaticTokenClient(token,
+//	    astra.WithAstraURI(astraURI),
+//	    astra.WithRetryPolicy(&astra.ExponentialBackoffRetryPolicy{MaxRetries: 3}),
+//	)
+//	...
+//	rows, err := c.Query("SELECT * FROM users WHERE id = ?", id).Idempotent(true).Exec()
+//
+// # Tracing
+//
+// Server-side tracing is enabled per query with Query.Trace. The trace of
+// each page request is available from Iter.Trace once the page is fetched.
+//
+//	it := c.Query("SELECT * FROM users WHERE id = ?", id).Trace(true).Iter()
+//	for it.Next() {
+//	    ...
+//	}
+//	if tr := it.Trace(); tr != nil {
+//	    for _, e := range tr.Events {
+//	        log.Printf("%v %s: %s", e.SourceElapsed, e.Source, e.Activity)
+//	    }
+//	}
+//
+// # Schema
+//
+// Client.Schema provides metadata about keyspaces, tables, columns, indexes,
+// user-defined types and materialized views, read from system_schema and
+// cached until a schema change is made through the client.
+//
+//	tm, err := c.Schema().Table("example", "users")
+//	...
+//	for _, col := range tm.PartitionKey {
+//	    fmt.Printf("%s %s\n", col.Name, col.Type)
+//	}
+//
+// # User-defined types
+//
+// UDT values are written from structs, with fields mapped to UDT fields by
+// their `cql` tag or, if untagged, the snake case form of the field name, or
+// from a UDT, a map of UDT field names to values. UDT values are read as a
+// map[string]any, and can be scanned into structs with Row.Scan.
+//
+//	type Address struct {
+//	    Street string `cql:"street"`
+//	    Zip    int    `cql:"zip_code"`
+//	}
+//
+//	_, err := c.Query("INSERT INTO users (id, home) VALUES (?, ?)", id, Address{...}).Exec()
+//
+//	var home Address
+//	err = row.Scan(&home)
+//
+// Other maps, including a map[string]any, are written as CQL maps, so a
+// map[string]any read from a UDT must be converted to a UDT to write it back.
+// Structs implementing driver.Valuer, such as sql.NullString, are written as
+// the value they return rather than as UDTs.
+//
+// # Paging
+//
+// Query.Exec fetches every page of results before returning. To process large
+// results incrementally, use Query.Iter, which fetches pages as needed.
+//
+//	it := c.Query("SELECT * FROM table").PageSize(100).Iter()
+//	for it.Next() {
+//	    var id uuid.UUID
+//	    if err := it.Scan(&id); err != nil {
+//	        // Handle error.
+//	    }
+//	}
+//	if err := it.Close(); err != nil {
+//	    // Handle error.
+//	}
+//
+// Iter.PageState returns an opaque token which can be passed to
+// Query.PagingState to resume iteration from the following page later.
+//
 // [Astra DB Manage application tokens]: https://docs.datastax.com/en/astra/docs/manage/org/managing-org.html#_manage_application_tokens
 // [Astra DB Table-based authentication/authorization]: https://stargate.io/docs/stargate/1.0/developers-guide/authnz.html#_table_based_authenticationauthorization
 package astra
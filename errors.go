@@ -0,0 +1,229 @@
+package astra
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Errors matched by a *QueryError with errors.Is, according to the cause of
+// the failure.
+var (
+	// ErrUnavailable indicates that not enough replicas were alive to satisfy
+	// the requested consistency level.
+	ErrUnavailable = errors.New("not enough replicas available")
+	// ErrReadTimeout indicates that not enough replicas responded to a read
+	// in time to satisfy the requested consistency level.
+	ErrReadTimeout = errors.New("read timeout")
+	// ErrWriteTimeout indicates that not enough replicas acknowledged a write
+	// in time to satisfy the requested consistency level.
+	ErrWriteTimeout = errors.New("write timeout")
+	// ErrReadFailure indicates that a read failed on one or more replicas.
+	ErrReadFailure = errors.New("read failure")
+	// ErrWriteFailure indicates that a write failed on one or more replicas.
+	ErrWriteFailure = errors.New("write failure")
+	// ErrFunctionFailure indicates that a user-defined function failed.
+	ErrFunctionFailure = errors.New("function failure")
+	// ErrCASWriteUnknown indicates that the outcome of a lightweight
+	// transaction is unknown.
+	ErrCASWriteUnknown = errors.New("lightweight transaction write unknown")
+	// ErrAlreadyExists indicates that a keyspace or table to be created
+	// already exists.
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrOverloaded indicates that the server is overloaded.
+	ErrOverloaded = errors.New("server overloaded")
+	// ErrInvalidQuery indicates that a query is syntactically or semantically
+	// invalid.
+	ErrInvalidQuery = errors.New("invalid query")
+	// ErrUnauthorized indicates that the authenticated user lacks permission
+	// to perform a query.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrUnauthenticated indicates that the request could not be
+	// authenticated, e.g. because of an invalid or expired token.
+	ErrUnauthenticated = errors.New("unauthenticated")
+)
+
+// QueryError is returned when Stargate fails to execute a query or batch
+// query. Use errors.Is to test for a specific cause, e.g. ErrUnavailable, and
+// errors.As to inspect the details of the failure.
+//
+//	var qerr *astra.QueryError
+//	if errors.As(err, &qerr) && errors.Is(err, astra.ErrUnavailable) {
+//	    log.Printf("%d of %d replicas alive", qerr.Received, qerr.Required)
+//	}
+type QueryError struct {
+	// Code is the gRPC status code of the failure.
+	Code codes.Code
+	// Message is the failure message returned by the server.
+	Message string
+
+	// Consistency is the consistency level of the failed query, for
+	// unavailable, timeout, failure and CAS write unknown errors.
+	Consistency Consistency
+	// Required is the number of replicas required to satisfy the
+	// consistency level.
+	Required int
+	// Received is the number of replicas which responded, or for unavailable
+	// errors, the number of replicas known to be alive.
+	Received int
+	// NumFailures is the number of replicas which failed, for read and write
+	// failures.
+	NumFailures int
+	// DataPresent reports whether the replica asked for data responded, for
+	// read timeouts and failures.
+	DataPresent bool
+	// WriteType is the type of the write which failed, e.g. "SIMPLE" or
+	// "BATCH", for write timeouts and failures.
+	WriteType string
+
+	// Keyspace is the keyspace of the existing keyspace or table for already
+	// exists errors, or of the failed function for function failures.
+	Keyspace string
+	// Table is the existing table for already exists errors, or empty if the
+	// existing entity is a keyspace.
+	Table string
+	// Function is the name of the failed function for function failures.
+	Function string
+	// ArgTypes are the CQL argument types of the failed function for function
+	// failures.
+	ArgTypes []string
+
+	// Attempts is the number of times the query was attempted, including
+	// retries made by the retry policy.
+	Attempts int
+
+	kind error
+	err  error
+}
+
+// Error returns the failure message along with its gRPC status code.
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%s (%s)", e.Message, e.Code)
+}
+
+// Is reports whether target is the sentinel error describing the cause of the
+// failure, e.g. ErrWriteTimeout.
+func (e *QueryError) Is(target error) bool {
+	return e.kind != nil && target == e.kind
+}
+
+// Unwrap returns the underlying gRPC error.
+func (e *QueryError) Unwrap() error {
+	return e.err
+}
+
+// errorDetailTypes are the Stargate messages describing the cause of a
+// failure. Stargate sends them as binary trailers keyed by their full message
+// name, e.g. "stargate.unavailable-bin".
+var errorDetailTypes = []proto.Message{
+	&pb.Unavailable{},
+	&pb.ReadTimeout{},
+	&pb.WriteTimeout{},
+	&pb.ReadFailure{},
+	&pb.WriteFailure{},
+	&pb.FunctionFailure{},
+	&pb.CasWriteUnknown{},
+	&pb.AlreadyExists{},
+}
+
+// newQueryError converts an error returned by a Stargate request into a
+// *QueryError, decoding failure details from the gRPC status and trailer.
+func newQueryError(err error, trailer metadata.MD) *QueryError {
+	st := status.Convert(err)
+	res := &QueryError{
+		Code:    st.Code(),
+		Message: st.Message(),
+		err:     err,
+	}
+
+	switch st.Code() {
+	case codes.InvalidArgument:
+		res.kind = ErrInvalidQuery
+	case codes.PermissionDenied:
+		res.kind = ErrUnauthorized
+	case codes.Unauthenticated:
+		res.kind = ErrUnauthenticated
+	case codes.ResourceExhausted:
+		res.kind = ErrOverloaded
+	case codes.AlreadyExists:
+		res.kind = ErrAlreadyExists
+	}
+
+	for _, d := range errorDetails(st, trailer) {
+		res.setDetail(d)
+	}
+	return res
+}
+
+func errorDetails(st *status.Status, trailer metadata.MD) []any {
+	res := st.Details()
+	for _, t := range errorDetailTypes {
+		key := strings.ToLower(string(proto.MessageName(t))) + "-bin"
+		vs := trailer.Get(key)
+		if len(vs) == 0 {
+			continue
+		}
+		d := t.ProtoReflect().New().Interface()
+		if err := proto.Unmarshal([]byte(vs[0]), d); err != nil {
+			continue
+		}
+		res = append(res, d)
+	}
+	return res
+}
+
+func (e *QueryError) setDetail(detail any) {
+	switch d := detail.(type) {
+	case *pb.Unavailable:
+		e.kind = ErrUnavailable
+		e.Consistency = consistencyFromProto(d.Consistency)
+		e.Required = int(d.Required)
+		e.Received = int(d.Alive)
+	case *pb.ReadTimeout:
+		e.kind = ErrReadTimeout
+		e.Consistency = consistencyFromProto(d.Consistency)
+		e.Required = int(d.BlockFor)
+		e.Received = int(d.Received)
+		e.DataPresent = d.DataPresent
+	case *pb.WriteTimeout:
+		e.kind = ErrWriteTimeout
+		e.Consistency = consistencyFromProto(d.Consistency)
+		e.Required = int(d.BlockFor)
+		e.Received = int(d.Received)
+		e.WriteType = d.WriteType
+	case *pb.ReadFailure:
+		e.kind = ErrReadFailure
+		e.Consistency = consistencyFromProto(d.Consistency)
+		e.Required = int(d.BlockFor)
+		e.Received = int(d.Received)
+		e.NumFailures = int(d.NumFailures)
+		e.DataPresent = d.DataPresent
+	case *pb.WriteFailure:
+		e.kind = ErrWriteFailure
+		e.Consistency = consistencyFromProto(d.Consistency)
+		e.Required = int(d.BlockFor)
+		e.Received = int(d.Received)
+		e.NumFailures = int(d.NumFailures)
+		e.WriteType = d.WriteType
+	case *pb.FunctionFailure:
+		e.kind = ErrFunctionFailure
+		e.Keyspace = d.Keyspace
+		e.Function = d.Function
+		e.ArgTypes = d.ArgTypes
+	case *pb.CasWriteUnknown:
+		e.kind = ErrCASWriteUnknown
+		e.Consistency = consistencyFromProto(d.Consistency)
+		e.Required = int(d.BlockFor)
+		e.Received = int(d.Received)
+	case *pb.AlreadyExists:
+		e.kind = ErrAlreadyExists
+		e.Keyspace = d.Keyspace
+		e.Table = d.Table
+	}
+}
@@ -0,0 +1,111 @@
+package astra
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewQueryError(t *testing.T) {
+	writeTimeout, err := proto.Marshal(&pb.WriteTimeout{
+		Consistency: pb.Consistency_QUORUM,
+		Received:    1,
+		BlockFor:    2,
+		WriteType:   "SIMPLE",
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal write timeout: %v", err)
+	}
+	unavailable, err := status.New(codes.Unavailable, "cannot achieve consistency").
+		WithDetails(&pb.Unavailable{Consistency: pb.Consistency_LOCAL_QUORUM, Required: 2, Alive: 1})
+	if err != nil {
+		t.Fatalf("failed to create status with details: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		trailer  metadata.MD
+		wantKind error
+		want     *QueryError
+	}{
+		{
+			name:     "status details",
+			err:      unavailable.Err(),
+			wantKind: ErrUnavailable,
+			want: &QueryError{
+				Code:        codes.Unavailable,
+				Message:     "cannot achieve consistency",
+				Consistency: ConsistencyLocalQuorum,
+				Required:    2,
+				Received:    1,
+			},
+		},
+		{
+			name:     "trailer details",
+			err:      status.Error(codes.DeadlineExceeded, "operation timed out"),
+			trailer:  metadata.Pairs("stargate.writetimeout-bin", string(writeTimeout)),
+			wantKind: ErrWriteTimeout,
+			want: &QueryError{
+				Code:        codes.DeadlineExceeded,
+				Message:     "operation timed out",
+				Consistency: ConsistencyQuorum,
+				Required:    2,
+				Received:    1,
+				WriteType:   "SIMPLE",
+			},
+		},
+		{
+			name:     "invalid query",
+			err:      status.Error(codes.InvalidArgument, "line 1:0 no viable alternative"),
+			wantKind: ErrInvalidQuery,
+			want: &QueryError{
+				Code:    codes.InvalidArgument,
+				Message: "line 1:0 no viable alternative",
+			},
+		},
+		{
+			name:     "unauthorized",
+			err:      status.Error(codes.PermissionDenied, "no SELECT permission"),
+			wantKind: ErrUnauthorized,
+			want: &QueryError{
+				Code:    codes.PermissionDenied,
+				Message: "no SELECT permission",
+			},
+		},
+		{
+			name: "unknown",
+			err:  status.Error(codes.Internal, "internal error"),
+			want: &QueryError{
+				Code:    codes.Internal,
+				Message: "internal error",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newQueryError(tt.err, tt.trailer)
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreUnexported(QueryError{})); diff != "" {
+				t.Errorf("newQueryError(%v) unexpected difference (-want +got):\n%s", tt.err, diff)
+			}
+
+			wrapped := fmt.Errorf("failed to execute query: %w", got)
+			if tt.wantKind != nil && !errors.Is(wrapped, tt.wantKind) {
+				t.Errorf("errors.Is(newQueryError(%v), %v) got false, want true", tt.err, tt.wantKind)
+			}
+			for _, kind := range []error{ErrUnavailable, ErrWriteTimeout, ErrInvalidQuery, ErrUnauthorized} {
+				if kind != tt.wantKind && errors.Is(wrapped, kind) {
+					t.Errorf("errors.Is(newQueryError(%v), %v) got true, want false", tt.err, kind)
+				}
+			}
+		})
+	}
+}
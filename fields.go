@@ -0,0 +1,167 @@
+package astra
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// field describes a struct field mapped to a CQL column or UDT field.
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	key       bool
+}
+
+var (
+	fieldCache    sync.Map // map[reflect.Type][]field
+	fieldMapCache sync.Map // map[reflect.Type]map[string]field
+)
+
+// cqlFields returns the fields of struct type t mapped to CQL names, in
+// declaration order.
+//
+// A field's CQL name is taken from its `cql` tag, falling back to the snake
+// case form of the field name. Fields tagged `cql:"-"` and unexported fields
+// are ignored. The fields of untagged embedded structs are promoted as if they
+// were fields of t, with shallower fields taking precedence. A tag may specify
+// the "omitempty" and "key" options after the name, e.g.
+// `cql:"name,omitempty"`.
+func cqlFields(t reflect.Type) []field {
+	if fs, ok := fieldCache.Load(t); ok {
+		return fs.([]field)
+	}
+	fs := typeFields(t, nil, map[string]bool{})
+	fieldCache.Store(t, fs)
+	return fs
+}
+
+// cqlFieldMap returns the fields of struct type t mapped by cqlFields, keyed by
+// CQL name.
+func cqlFieldMap(t reflect.Type) map[string]field {
+	if m, ok := fieldMapCache.Load(t); ok {
+		return m.(map[string]field)
+	}
+	fs := cqlFields(t)
+	m := make(map[string]field, len(fs))
+	for _, f := range fs {
+		m[f.name] = f
+	}
+	fieldMapCache.Store(t, m)
+	return m
+}
+
+func typeFields(t reflect.Type, index []int, seen map[string]bool) []field {
+	var res []field
+	var embedded []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, hasTag := sf.Tag.Lookup("cql")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, sf)
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = toSnakeCase(sf.Name)
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		res = append(res, field{
+			name:      name,
+			index:     appendIndex(index, sf.Index),
+			omitEmpty: hasTag && hasOption(opts, "omitempty"),
+			key:       hasTag && hasOption(opts, "key"),
+		})
+	}
+	for _, sf := range embedded {
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		res = append(res, typeFields(ft, appendIndex(index, sf.Index), seen)...)
+	}
+	return res
+}
+
+func appendIndex(index, i []int) []int {
+	res := make([]int, 0, len(index)+len(i))
+	res = append(res, index...)
+	return append(res, i...)
+}
+
+func hasOption(opts, opt string) bool {
+	for opts != "" {
+		var o string
+		o, opts, _ = strings.Cut(opts, ",")
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldByIndex returns the field of struct v at index, or false if it is
+// unreachable through a nil embedded pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// fieldByIndexAlloc is like fieldByIndex, but allocates nil embedded pointers
+// as needed. v must be addressable.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// toSnakeCase converts a Go identifier such as "UserID" to snake case, e.g.
+// "user_id".
+func toSnakeCase(s string) string {
+	rs := []rune(s)
+	var b strings.Builder
+	for i, r := range rs {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(rs[i-1]) || unicode.IsDigit(rs[i-1]) ||
+				(i+1 < len(rs) && unicode.IsLower(rs[i+1]) && unicode.IsUpper(rs[i-1]))) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,49 @@
+package astra
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestToSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"ID":         "id",
+		"Name":       "name",
+		"UserID":     "user_id",
+		"HTTPServer": "http_server",
+		"Address2":   "address2",
+		"already_ok": "already_ok",
+	}
+	for in, want := range tests {
+		if got := toSnakeCase(in); got != want {
+			t.Errorf("toSnakeCase(%q) got %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCqlFields(t *testing.T) {
+	type Base struct {
+		ID      string
+		Created int64 `cql:"created_at"`
+	}
+	type user struct {
+		*Base
+		Name    string `cql:"name,omitempty"`
+		ID      string `cql:"user_id,key"`
+		Skipped string `cql:"-"`
+		private string
+	}
+
+	got := cqlFields(reflect.TypeOf(user{}))
+	want := []field{
+		{name: "name", index: []int{1}, omitEmpty: true},
+		{name: "user_id", index: []int{2}, key: true},
+		{name: "id", index: []int{0, 0}},
+		{name: "created_at", index: []int{0, 1}},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(field{})); diff != "" {
+		t.Errorf("cqlFields() unexpected difference (-want +got):\n%s", diff)
+	}
+}
@@ -6,6 +6,7 @@ require (
 	github.com/docker/go-connections v0.4.0
 	github.com/google/go-cmp v0.5.6
 	github.com/google/uuid v1.3.0
+	github.com/shopspring/decimal v1.3.1
 	github.com/stargate/stargate-grpc-go-client v0.0.0-20220516194209-7553b43cf28d
 	github.com/testcontainers/testcontainers-go v0.13.0
 	google.golang.org/grpc v1.47.0
@@ -34,7 +35,6 @@ require (
 	github.com/opencontainers/image-spec v1.0.2 // indirect
 	github.com/opencontainers/runc v1.0.2 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
-	github.com/shopspring/decimal v1.3.1 // indirect
 	github.com/sirupsen/logrus v1.8.1 // indirect
 	go.opencensus.io v0.22.3 // indirect
 	golang.org/x/net v0.0.0-20211108170745-6635138e15ea // indirect
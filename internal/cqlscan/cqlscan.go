@@ -0,0 +1,63 @@
+// Package cqlscan scans CQL for the string literals, quoted identifiers and
+// comments inside which bind markers and statement separators have no
+// meaning.
+package cqlscan
+
+import "strings"
+
+// Kind is the kind of a token returned by Next.
+type Kind int
+
+const (
+	// Other is a single byte outside of literals and comments.
+	Other Kind = iota
+	// Literal is a string literal, quoted identifier or $$ string.
+	Literal
+	// LineComment is a "--" or "//" comment, including the newline ending it.
+	LineComment
+	// BlockComment is a "/* */" comment.
+	BlockComment
+)
+
+// Next returns the kind of the token of cql starting at i, and the index
+// following it. Unterminated literals and comments run to the end of cql.
+func Next(cql string, i int) (Kind, int) {
+	rest := cql[i:]
+	switch {
+	case rest[0] == '\'' || rest[0] == '"':
+		return Literal, skipQuoted(cql, i)
+	case strings.HasPrefix(rest, "$$"):
+		return Literal, skipUntil(cql, i+2, "$$")
+	case strings.HasPrefix(rest, "--") || strings.HasPrefix(rest, "//"):
+		return LineComment, skipUntil(cql, i+2, "\n")
+	case strings.HasPrefix(rest, "/*"):
+		return BlockComment, skipUntil(cql, i+2, "*/")
+	}
+	return Other, i + 1
+}
+
+// skipQuoted returns the index following the string literal or quoted
+// identifier starting at i. Quotes are escaped by doubling them.
+func skipQuoted(cql string, i int) int {
+	q := cql[i]
+	for j := i + 1; j < len(cql); j++ {
+		if cql[j] != q {
+			continue
+		}
+		if j+1 < len(cql) && cql[j+1] == q {
+			j++
+			continue
+		}
+		return j + 1
+	}
+	return len(cql)
+}
+
+// skipUntil returns the index following the first occurrence of end in cql at
+// or after i, or len(cql) if there is none.
+func skipUntil(cql string, i int, end string) int {
+	if n := strings.Index(cql[i:], end); n >= 0 {
+		return i + n + len(end)
+	}
+	return len(cql)
+}
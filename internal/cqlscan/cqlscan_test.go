@@ -0,0 +1,36 @@
+package cqlscan
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNext(t *testing.T) {
+	type token struct {
+		Kind Kind
+		Text string
+	}
+	in := "a'b''c'\"d\"$$e;$$--f\n//g\n/*h*/'i"
+
+	var got []token
+	for i := 0; i < len(in); {
+		kind, j := Next(in, i)
+		got = append(got, token{kind, in[i:j]})
+		i = j
+	}
+
+	want := []token{
+		{Other, "a"},
+		{Literal, "'b''c'"},
+		{Literal, `"d"`},
+		{Literal, "$$e;$$"},
+		{LineComment, "--f\n"},
+		{LineComment, "//g\n"},
+		{BlockComment, "/*h*/"},
+		{Literal, "'i"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Next(%q) tokens mismatch (-want +got):\n%s", in, diff)
+	}
+}
@@ -0,0 +1,8 @@
+// Package hooks gives the other packages of the module access to unexported
+// behavior of package astra. The hooks are installed by package astra when it
+// is initialized.
+package hooks
+
+// SetQueryErr records err on q, an *astra.Query, to be returned when the query
+// is executed.
+var SetQueryErr func(q any, err error)
@@ -0,0 +1,99 @@
+package astra
+
+import (
+	"context"
+	"fmt"
+)
+
+// Iter iterates over the rows returned by a Query, transparently fetching
+// subsequent pages of results. Use Query.Iter to create an Iter.
+//
+//	it := c.Query("SELECT * FROM table").PageSize(100).Iter()
+//	for it.Next() {
+//	    // Do something with it.Row().
+//	}
+//	if err := it.Close(); err != nil {
+//	    // Handle error.
+//	}
+type Iter struct {
+	ctx   context.Context
+	query *Query
+
+	rows    Rows
+	pos     int
+	row     *Row
+	next    []byte
+	trace   *Trace
+	fetched bool
+
+	err    error
+	closed bool
+}
+
+// Next advances the Iter to the next row, fetching the next page of results
+// if needed. It returns false when there are no more rows or an error
+// occurred; call Err or Close to distinguish between the two.
+func (it *Iter) Next() bool {
+	if it.err != nil || it.closed {
+		return false
+	}
+	for it.pos >= len(it.rows) {
+		if it.fetched && len(it.next) == 0 {
+			it.row = nil
+			return false
+		}
+		res, err := it.query.client.execQueryPage(it.ctx, it.query, it.next)
+		if err != nil {
+			it.err = fmt.Errorf("failed to fetch page: %w", err)
+			it.row = nil
+			return false
+		}
+		it.rows, it.pos, it.next, it.trace, it.fetched = res.Rows, 0, res.PagingState, res.Trace, true
+	}
+	it.row = &it.rows[it.pos]
+	it.pos++
+	return true
+}
+
+// Row returns the current row, or nil if Next has not been called or
+// returned false.
+func (it *Iter) Row() *Row {
+	return it.row
+}
+
+// Scan copies the values from the current row into the provided pointers.
+func (it *Iter) Scan(dest ...any) error {
+	if it.row == nil {
+		return fmt.Errorf("no current row to scan")
+	}
+	return it.row.Scan(dest...)
+}
+
+// PageState returns the opaque paging state of the page following the one
+// containing the current row, or nil if there are no further pages. Pass it to
+// Query.PagingState to resume the query from that page later, for example to
+// implement stateless pagination across requests.
+func (it *Iter) PageState() []byte {
+	return it.next
+}
+
+// Trace returns the server-side trace of the request which fetched the page
+// containing the current row, or nil if tracing was not enabled with
+// Query.Trace.
+func (it *Iter) Trace() *Trace {
+	return it.trace
+}
+
+// Err returns the error, if any, encountered while fetching rows.
+func (it *Iter) Err() error {
+	return it.err
+}
+
+// Close stops the iteration and returns the error, if any, encountered while
+// fetching rows. Subsequent calls to Next return false.
+func (it *Iter) Close() error {
+	it.closed = true
+	it.rows = nil
+	it.row = nil
+	return it.err
+}
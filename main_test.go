@@ -45,11 +45,11 @@ func TestMain(m *testing.M) {
 		}
 		token = *testToken
 
-		bundle, err := loadBundleZipFromPath(*testSecureConnectBundlePath)
+		bundle, err := LoadBundle(*testSecureConnectBundlePath)
 		if err != nil {
 			log.Fatalf("failed to load secure connect bundle: %v", err)
 		}
-		endpoint = bundle.host
+		endpoint = bundle.Addr()
 
 		createTestClient = func() (*Client, error) {
 			c, err := NewStaticTokenClient(*testToken, WithSecureConnectBundle(*testSecureConnectBundlePath))
@@ -0,0 +1,413 @@
+// Package migrate applies versioned CQL schema migrations with an
+// astra.Client.
+//
+// Migrations are read from the .cql files at the root of an fs.FS, such as an
+// embed.FS, named "<version>_<name>.cql", e.g. "0001_create_users.cql", and
+// are applied in version order. Each file may contain several statements
+// separated by semicolons. Applied versions and the checksums of their files
+// are recorded in a tracking table, and a lock held with a lightweight
+// transaction prevents concurrent runs from applying migrations twice.
+//
+//	//go:embed migrations/*.cql
+//	var migrations embed.FS
+//
+//	sub, _ := fs.Sub(migrations, "migrations")
+//	m, err := migrate.New(c, sub, migrate.WithKeyspace("example"))
+//	if err != nil {
+//	    // Handle error.
+//	}
+//	applied, err := m.Up(ctx)
+//
+// CQL has no transactional DDL: if a statement of a migration fails, the
+// statements before it remain applied and the migration is not recorded.
+// Migrations should therefore use IF NOT EXISTS and IF EXISTS clauses so that
+// they can be safely re-run.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/datastax-ext/astra-go-sdk"
+)
+
+const (
+	defaultTable   = "schema_migrations"
+	defaultLockTTL = 10 * time.Minute
+
+	// lockName is the key of the single row of the lock table.
+	lockName = "migrate"
+)
+
+var (
+	// ErrLocked is returned by Migrator.Up when another run holds the
+	// migration lock.
+	ErrLocked = errors.New("migrations are locked by another run")
+	// ErrChecksumMismatch is returned when the file of an applied migration
+	// has changed since it was applied.
+	ErrChecksumMismatch = errors.New("applied migration has changed")
+	// ErrLockLost is returned by Migrator.Up when the migration lock expired
+	// before the run completed, so another run may have applied migrations
+	// concurrently.
+	ErrLockLost = errors.New("migration lock expired before the run completed")
+)
+
+// Migration is a versioned CQL schema migration.
+type Migration struct {
+	Version int64
+	Name    string
+	// Statements are the CQL statements of the migration, in order.
+	Statements []string
+	// Checksum is the hex encoded SHA-256 checksum of the migration file.
+	Checksum string
+}
+
+// Status is the status of a migration.
+type Status struct {
+	Migration
+	// Applied reports whether the migration has been applied.
+	Applied bool
+	// AppliedAt is the time the migration was applied, if it has been.
+	AppliedAt time.Time
+	// ChecksumMismatch reports whether the migration file has changed since
+	// the migration was applied.
+	ChecksumMismatch bool
+}
+
+// Option is an option for a Migrator.
+type Option func(*Migrator)
+
+// WithKeyspace sets the keyspace of the tracking and lock tables. Defaults
+// to the default keyspace of the client.
+func WithKeyspace(keyspace string) Option {
+	return func(m *Migrator) {
+		m.keyspace = keyspace
+	}
+}
+
+// WithTable sets the name of the tracking table. The lock table is named
+// after it with a "_lock" suffix. Defaults to "schema_migrations".
+func WithTable(table string) Option {
+	return func(m *Migrator) {
+		m.table = table
+	}
+}
+
+// WithLockTTL sets how long the migration lock is held before it expires,
+// should a run fail to release it, which must be at least one second.
+// Defaults to 10 minutes. The lock is not
+// renewed, so the TTL must exceed the duration of the longest run: Up returns
+// an error wrapping ErrLockLost if the lock expired before it completed.
+func WithLockTTL(ttl time.Duration) Option {
+	return func(m *Migrator) {
+		m.lockTTL = ttl
+	}
+}
+
+// WithOwner sets the owner recorded in the migration lock, reported when
+// another run fails to acquire it. Defaults to the host name and process ID.
+func WithOwner(owner string) Option {
+	return func(m *Migrator) {
+		m.owner = owner
+	}
+}
+
+// Migrator applies migrations. Use New to create a Migrator.
+type Migrator struct {
+	client     *astra.Client
+	migrations []Migration
+
+	keyspace string
+	table    string
+	lockTTL  time.Duration
+	owner    string
+}
+
+// New creates a new Migrator applying the migrations read from fsys with c.
+func New(c *astra.Client, fsys fs.FS, opts ...Option) (*Migrator, error) {
+	m := &Migrator{
+		client:  c,
+		table:   defaultTable,
+		lockTTL: defaultLockTTL,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.lockTTL < time.Second {
+		return nil, fmt.Errorf("invalid lock TTL %v, must be at least one second", m.lockTTL)
+	}
+	if m.owner == "" {
+		host, _ := os.Hostname()
+		m.owner = fmt.Sprintf("%s:%d", host, os.Getpid())
+	}
+
+	var err error
+	m.migrations, err = Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Load reads the migrations from the .cql files at the root of fsys, in
+// version order. Other files are ignored.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	var res []Migration
+	versions := map[int64]string{}
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".cql" {
+			continue
+		}
+		version, name, err := parseFileName(e.Name())
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := versions[version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %d: %q and %q", version, other, e.Name())
+		}
+		versions[version] = e.Name()
+
+		b, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %q: %w", e.Name(), err)
+		}
+		sum := sha256.Sum256(b)
+		res = append(res, Migration{
+			Version:    version,
+			Name:       name,
+			Statements: splitStatements(string(b)),
+			Checksum:   hex.EncodeToString(sum[:]),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+	return res, nil
+}
+
+// parseFileName parses a migration file name of the form
+// "<version>_<name>.cql".
+func parseFileName(file string) (int64, string, error) {
+	v, name, ok := strings.Cut(strings.TrimSuffix(file, ".cql"), "_")
+	version, err := strconv.ParseInt(v, 10, 64)
+	if !ok || err != nil || version < 0 || name == "" {
+		return 0, "", fmt.Errorf("invalid migration file name %q, want \"<version>_<name>.cql\"", file)
+	}
+	return version, name, nil
+}
+
+// Status returns the status of all migrations, in version order. It only
+// reads the tracking table, and reports no migrations as applied if the table
+// does not exist yet.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Status, len(m.migrations))
+	for i, mig := range m.migrations {
+		res[i].Migration = mig
+		if a, ok := applied[mig.Version]; ok {
+			res[i].Applied = true
+			res[i].AppliedAt = time.UnixMilli(a.AppliedAt)
+			res[i].ChecksumMismatch = a.Checksum != mig.Checksum
+		}
+	}
+	return res, nil
+}
+
+// DryRun returns the migrations which Up would apply, in order, without
+// applying them or creating the tracking table. It returns an error wrapping ErrChecksumMismatch if an
+// applied migration has changed.
+func (m *Migrator) DryRun(ctx context.Context) ([]Migration, error) {
+	st, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return pending(st)
+}
+
+// Up applies the migrations which have not been applied yet, in order, and
+// returns them. It returns an error wrapping ErrLocked if another run holds
+// the migration lock, and an error wrapping ErrChecksumMismatch, without
+// applying any migrations, if an applied migration has changed. If the lock
+// expired before the run completed, it returns the applied migrations and an
+// error wrapping ErrLockLost.
+func (m *Migrator) Up(ctx context.Context) (done []Migration, err error) {
+	if err := m.createTables(ctx); err != nil {
+		return nil, err
+	}
+	if err := m.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if uerr := m.unlock(); err == nil {
+			err = uerr
+		}
+	}()
+
+	st, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	todo, err := pending(st)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, mig := range todo {
+		if err := m.apply(ctx, mig); err != nil {
+			return done, err
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+func pending(st []Status) ([]Migration, error) {
+	var res []Migration
+	for _, s := range st {
+		if s.ChecksumMismatch {
+			return nil, fmt.Errorf("migration %d_%s: %w", s.Version, s.Name, ErrChecksumMismatch)
+		}
+		if !s.Applied {
+			res = append(res, s.Migration)
+		}
+	}
+	return res, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, mig Migration) error {
+	for i, stmt := range mig.Statements {
+		if _, err := m.client.Query(stmt).ExecContext(ctx); err != nil {
+			return fmt.Errorf("failed to apply migration %d_%s, statement %d: %w", mig.Version, mig.Name, i+1, err)
+		}
+	}
+	_, err := m.client.Query(
+		fmt.Sprintf(`INSERT INTO %s (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)`, m.tableName()),
+		mig.Version, mig.Name, mig.Checksum, time.Now(),
+	).ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to record migration %d_%s: %w", mig.Version, mig.Name, err)
+	}
+	return nil
+}
+
+type appliedRow struct {
+	Version  int64  `cql:"version"`
+	Checksum string `cql:"checksum"`
+	// AppliedAt is in milliseconds since the Unix epoch, as timestamps are
+	// read.
+	AppliedAt int64 `cql:"applied_at"`
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int64]appliedRow, error) {
+	rows, err := m.client.Query(
+		fmt.Sprintf(`SELECT version, checksum, applied_at FROM %s`, m.tableName()),
+	).ExecContext(ctx)
+	if isMissingTable(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	rs, err := astra.ScanAll[appliedRow](rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	res := make(map[int64]appliedRow, len(rs))
+	for _, r := range rs {
+		res[r.Version] = r
+	}
+	return res, nil
+}
+
+// isMissingTable reports whether err is the error returned when querying a
+// table which does not exist.
+func isMissingTable(err error) bool {
+	var qerr *astra.QueryError
+	return errors.Is(err, astra.ErrInvalidQuery) && errors.As(err, &qerr) &&
+		strings.Contains(qerr.Message, "unconfigured table")
+}
+
+func (m *Migrator) createTables(ctx context.Context) error {
+	stmts := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			version bigint PRIMARY KEY,
+			name text,
+			checksum text,
+			applied_at timestamp
+		)`, m.tableName()),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			name text PRIMARY KEY,
+			owner text,
+			acquired_at timestamp
+		)`, m.lockTableName()),
+	}
+	for _, stmt := range stmts {
+		if _, err := m.client.Query(stmt).ExecContext(ctx); err != nil {
+			return fmt.Errorf("failed to create migration tables: %w", err)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) lock(ctx context.Context) error {
+	existing := map[string]any{}
+	applied, err := m.client.Query(
+		fmt.Sprintf(`INSERT INTO %s (name, owner, acquired_at) VALUES (?, ?, ?) IF NOT EXISTS USING TTL %d`,
+			m.lockTableName(), int64(m.lockTTL/time.Second)),
+		lockName, m.owner, time.Now(),
+	).MapExecCASContext(ctx, existing)
+	if err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if !applied {
+		return fmt.Errorf("%w: held by %v since %v", ErrLocked, existing["owner"], existing["acquired_at"])
+	}
+	return nil
+}
+
+// unlock releases the migration lock, and returns an error wrapping
+// ErrLockLost if it is no longer held by m. It uses a new context so that the
+// lock is released even if the context passed to Up was canceled.
+func (m *Migrator) unlock() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	applied, err := m.client.Query(
+		fmt.Sprintf(`DELETE FROM %s WHERE name = ? IF owner = ?`, m.lockTableName()),
+		lockName, m.owner,
+	).ExecCASContext(ctx)
+	if err != nil {
+		// The lock expires after its TTL, so failing to release it is not
+		// reported.
+		return nil
+	}
+	if !applied {
+		return ErrLockLost
+	}
+	return nil
+}
+
+func (m *Migrator) tableName() string {
+	if m.keyspace == "" {
+		return m.table
+	}
+	return m.keyspace + "." + m.table
+}
+
+func (m *Migrator) lockTableName() string {
+	return m.tableName() + "_lock"
+}
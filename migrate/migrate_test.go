@@ -0,0 +1,381 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/datastax-ext/astra-go-sdk"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_email.cql": {Data: []byte(`ALTER TABLE users ADD email text;`)},
+		"0001_create_users.cql": {Data: []byte(`
+			-- Users of the application.
+			CREATE TABLE IF NOT EXISTS users (id uuid PRIMARY KEY, name text);
+			CREATE INDEX IF NOT EXISTS users_name ON users (name);
+		`)},
+		"README.md": {Data: []byte(`Not a migration.`)},
+	}
+
+	got, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	want := []Migration{
+		{
+			Version: 1,
+			Name:    "create_users",
+			Statements: []string{
+				`CREATE TABLE IF NOT EXISTS users (id uuid PRIMARY KEY, name text)`,
+				`CREATE INDEX IF NOT EXISTS users_name ON users (name)`,
+			},
+		},
+		{
+			Version:    2,
+			Name:       "add_email",
+			Statements: []string{`ALTER TABLE users ADD email text`},
+		},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(Migration{}, "Checksum")); diff != "" {
+		t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+	}
+	if got[0].Checksum == "" || got[0].Checksum == got[1].Checksum {
+		t.Errorf("Load() got checksums %q and %q, want distinct checksums", got[0].Checksum, got[1].Checksum)
+	}
+}
+
+func TestLoad_errors(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"bad name": {"create_users.cql": {}},
+		"no name":  {"0001.cql": {}},
+		"duplicate": {
+			"0001_a.cql": {},
+			"1_b.cql":    {},
+		},
+	}
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(fsys); err == nil {
+				t.Errorf("Load() got nil error, want error")
+			}
+		})
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	in := `INSERT INTO t (a) VALUES ('x;y'); /* a; comment */
+		UPDATE "odd;name" SET b = 1 WHERE a = 'it''s;'; // trailing; comment
+		;
+		CREATE FUNCTION f() RETURNS NULL ON NULL INPUT RETURNS int LANGUAGE java AS $$ return 1; $$`
+	want := []string{
+		`INSERT INTO t (a) VALUES ('x;y')`,
+		`UPDATE "odd;name" SET b = 1 WHERE a = 'it''s;'`,
+		`CREATE FUNCTION f() RETURNS NULL ON NULL INPUT RETURNS int LANGUAGE java AS $$ return 1; $$`,
+	}
+	if diff := cmp.Diff(want, splitStatements(in)); diff != "" {
+		t.Errorf("splitStatements() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPending(t *testing.T) {
+	st := []Status{
+		{Migration: Migration{Version: 1}, Applied: true},
+		{Migration: Migration{Version: 2}},
+		{Migration: Migration{Version: 3}},
+	}
+	got, err := pending(st)
+	if err != nil {
+		t.Fatalf("pending() unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0].Version != 2 || got[1].Version != 3 {
+		t.Errorf("pending() got %+v, want versions 2 and 3", got)
+	}
+
+	st[0].ChecksumMismatch = true
+	if _, err := pending(st); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("pending() got error %v, want %v", err, ErrChecksumMismatch)
+	}
+}
+
+// fakeStargate is a Stargate server keeping the tracking and lock tables of
+// a Migrator in memory, and recording the other statements it executes.
+type fakeStargate struct {
+	pb.UnimplementedStargateServer
+
+	mu         sync.Mutex
+	applied    map[int64]appliedRow
+	lockOwner  string
+	created    bool // whether the tracking table exists
+	creates    int  // of the tracking table
+	statements []string
+	// onStatement, if set, is called with the lock held for each other
+	// statement executed.
+	onStatement func(cql string)
+}
+
+func (s *fakeStargate) ExecuteQuery(_ context.Context, q *pb.Query) (*pb.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vals := q.GetValues().GetValues()
+	switch cql := q.Cql; {
+	case strings.HasPrefix(cql, "CREATE TABLE IF NOT EXISTS schema_migrations ("):
+		s.created = true
+		s.creates++
+		return &pb.Response{}, nil
+	case strings.HasPrefix(cql, "CREATE TABLE IF NOT EXISTS schema_migrations_lock"):
+		return &pb.Response{}, nil
+	case strings.HasPrefix(cql, "INSERT INTO schema_migrations_lock"):
+		if s.lockOwner != "" {
+			return casResponse(false, s.lockOwner), nil
+		}
+		s.lockOwner = vals[1].GetString_()
+		return casResponse(true, ""), nil
+	case strings.HasPrefix(cql, "DELETE FROM schema_migrations_lock"):
+		if s.lockOwner != vals[1].GetString_() {
+			return casResponse(false, s.lockOwner), nil
+		}
+		s.lockOwner = ""
+		return casResponse(true, ""), nil
+	case strings.HasPrefix(cql, "SELECT version, checksum, applied_at FROM schema_migrations"):
+		if !s.created {
+			return nil, status.Error(codes.InvalidArgument, "unconfigured table schema_migrations")
+		}
+		rs := &pb.ResultSet{Columns: []*pb.ColumnSpec{
+			{Name: "version", Type: basicType(pb.TypeSpec_BIGINT)},
+			{Name: "checksum", Type: basicType(pb.TypeSpec_VARCHAR)},
+			{Name: "applied_at", Type: basicType(pb.TypeSpec_TIMESTAMP)},
+		}}
+		for _, a := range s.applied {
+			rs.Rows = append(rs.Rows, &pb.Row{Values: []*pb.Value{
+				{Inner: &pb.Value_Int{Int: a.Version}},
+				{Inner: &pb.Value_String_{String_: a.Checksum}},
+				{Inner: &pb.Value_Int{Int: a.AppliedAt}},
+			}})
+		}
+		return &pb.Response{Result: &pb.Response_ResultSet{ResultSet: rs}}, nil
+	case strings.HasPrefix(cql, "INSERT INTO schema_migrations "):
+		s.applied[vals[0].GetInt()] = appliedRow{
+			Version:   vals[0].GetInt(),
+			Checksum:  vals[2].GetString_(),
+			AppliedAt: vals[3].GetInt(),
+		}
+		return &pb.Response{}, nil
+	default:
+		s.statements = append(s.statements, cql)
+		if s.onStatement != nil {
+			s.onStatement(cql)
+		}
+		return &pb.Response{}, nil
+	}
+}
+
+func basicType(t pb.TypeSpec_Basic) *pb.TypeSpec {
+	return &pb.TypeSpec{Spec: &pb.TypeSpec_Basic_{Basic: t}}
+}
+
+// casResponse returns the response to a conditional statement on the lock
+// table, with the existing owner if it was not applied.
+func casResponse(applied bool, owner string) *pb.Response {
+	rs := &pb.ResultSet{
+		Columns: []*pb.ColumnSpec{{Name: "[applied]", Type: basicType(pb.TypeSpec_BOOLEAN)}},
+		Rows:    []*pb.Row{{Values: []*pb.Value{{Inner: &pb.Value_Boolean{Boolean: applied}}}}},
+	}
+	if !applied {
+		rs.Columns = append(rs.Columns, &pb.ColumnSpec{Name: "owner", Type: basicType(pb.TypeSpec_VARCHAR)})
+		rs.Rows[0].Values = append(rs.Rows[0].Values, &pb.Value{Inner: &pb.Value_String_{String_: owner}})
+	}
+	return &pb.Response{Result: &pb.Response_ResultSet{ResultSet: rs}}
+}
+
+// newTestMigrator returns a Migrator of the migrations in fsys using a client
+// connected to a new fakeStargate.
+func newTestMigrator(t *testing.T, fsys fs.FS) (*Migrator, *fakeStargate) {
+	t.Helper()
+	fake := &fakeStargate{applied: map[int64]appliedRow{}}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	pb.RegisterStargateServer(srv, fake)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	c, err := astra.NewStaticTokenClient("token",
+		astra.WithAstraURI(lis.Addr().String()),
+		astra.WithInsecure(true),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	m, err := New(c, fsys, WithOwner("test"))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	return m, fake
+}
+
+var testMigrations = fstest.MapFS{
+	"0001_create_users.cql": {Data: []byte(`CREATE TABLE users (id int PRIMARY KEY); CREATE INDEX users_name ON users (name);`)},
+	"0002_add_email.cql":    {Data: []byte(`ALTER TABLE users ADD email text;`)},
+}
+
+func TestMigrator_Up(t *testing.T) {
+	m, fake := newTestMigrator(t, testMigrations)
+	ctx := context.Background()
+
+	got, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up() unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0].Version != 1 || got[1].Version != 2 {
+		t.Errorf("Up() got %+v, want versions 1 and 2", got)
+	}
+	wantStatements := []string{
+		`CREATE TABLE users (id int PRIMARY KEY)`,
+		`CREATE INDEX users_name ON users (name)`,
+		`ALTER TABLE users ADD email text`,
+	}
+	if diff := cmp.Diff(wantStatements, fake.statements); diff != "" {
+		t.Errorf("Up() statements mismatch (-want +got):\n%s", diff)
+	}
+	if fake.creates != 1 {
+		t.Errorf("Up() created tables %d times, want 1", fake.creates)
+	}
+	for _, mig := range got {
+		if a := fake.applied[mig.Version]; a.Checksum != mig.Checksum || a.AppliedAt == 0 {
+			t.Errorf("Up() recorded %+v for migration %d, want checksum %s and applied time", a, mig.Version, mig.Checksum)
+		}
+	}
+	if fake.lockOwner != "" {
+		t.Errorf("Up() left lock held by %q", fake.lockOwner)
+	}
+
+	// Applied migrations are not applied again.
+	got, err = m.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up() again unexpected error: %v", err)
+	}
+	if len(got) != 0 || len(fake.statements) != len(wantStatements) {
+		t.Errorf("Up() again got %+v and %d statements, want none", got, len(fake.statements)-len(wantStatements))
+	}
+}
+
+func TestMigrator_Up_locked(t *testing.T) {
+	m, fake := newTestMigrator(t, testMigrations)
+	fake.lockOwner = "other"
+
+	_, err := m.Up(context.Background())
+	if !errors.Is(err, ErrLocked) {
+		t.Errorf("Up() got error %v, want %v", err, ErrLocked)
+	}
+	if len(fake.statements) != 0 || fake.lockOwner != "other" {
+		t.Errorf("Up() executed %d statements and left lock held by %q, want none and %q", len(fake.statements), fake.lockOwner, "other")
+	}
+}
+
+func TestMigrator_Up_lockLost(t *testing.T) {
+	m, fake := newTestMigrator(t, testMigrations)
+	// The lock expires, and is acquired by another run, while migrating.
+	fake.onStatement = func(string) { fake.lockOwner = "other" }
+
+	got, err := m.Up(context.Background())
+	if !errors.Is(err, ErrLockLost) {
+		t.Errorf("Up() got error %v, want %v", err, ErrLockLost)
+	}
+	if len(got) != 2 {
+		t.Errorf("Up() got %d applied migrations, want 2", len(got))
+	}
+	if fake.lockOwner != "other" {
+		t.Errorf("Up() released lock held by %q", "other")
+	}
+}
+
+func TestMigrator_Up_checksumMismatch(t *testing.T) {
+	m, fake := newTestMigrator(t, testMigrations)
+	fake.applied[1] = appliedRow{Version: 1, Checksum: "changed", AppliedAt: 1}
+
+	if _, err := m.Up(context.Background()); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Up() got error %v, want %v", err, ErrChecksumMismatch)
+	}
+	if len(fake.statements) != 0 || fake.lockOwner != "" {
+		t.Errorf("Up() executed %d statements and left lock held by %q, want none", len(fake.statements), fake.lockOwner)
+	}
+}
+
+func TestMigrator_Status(t *testing.T) {
+	m, fake := newTestMigrator(t, testMigrations)
+	appliedAt := time.UnixMilli(1659312000000)
+	fake.created = true
+	fake.applied[1] = appliedRow{Version: 1, Checksum: m.migrations[0].Checksum, AppliedAt: appliedAt.UnixMilli()}
+
+	st, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() unexpected error: %v", err)
+	}
+	want := []Status{
+		{Migration: m.migrations[0], Applied: true, AppliedAt: appliedAt},
+		{Migration: m.migrations[1]},
+	}
+	if diff := cmp.Diff(want, st); diff != "" {
+		t.Errorf("Status() mismatch (-want +got):\n%s", diff)
+	}
+
+	dry, err := m.DryRun(context.Background())
+	if err != nil {
+		t.Fatalf("DryRun() unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(m.migrations[1:], dry); diff != "" {
+		t.Errorf("DryRun() mismatch (-want +got):\n%s", diff)
+	}
+	if len(fake.statements) != 0 || len(fake.applied) != 1 || fake.lockOwner != "" {
+		t.Errorf("DryRun() executed %d statements, recorded %d migrations and locked by %q, want none", len(fake.statements), len(fake.applied)-1, fake.lockOwner)
+	}
+	if fake.creates != 0 {
+		t.Errorf("Status() and DryRun() created tables %d times, want none", fake.creates)
+	}
+}
+
+func TestMigrator_Status_noTable(t *testing.T) {
+	m, fake := newTestMigrator(t, testMigrations)
+
+	st, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() unexpected error: %v", err)
+	}
+	if len(st) != 2 || st[0].Applied || st[1].Applied {
+		t.Errorf("Status() without tracking table got %+v, want nothing applied", st)
+	}
+	dry, err := m.DryRun(context.Background())
+	if err != nil {
+		t.Fatalf("DryRun() unexpected error: %v", err)
+	}
+	if len(dry) != 2 {
+		t.Errorf("DryRun() without tracking table got %d migrations, want 2", len(dry))
+	}
+	if fake.created {
+		t.Errorf("Status() and DryRun() created the tracking table")
+	}
+}
+
+func TestNew_lockTTL(t *testing.T) {
+	if _, err := New(nil, testMigrations, WithLockTTL(500*time.Millisecond)); err == nil {
+		t.Errorf("New() with lock TTL under one second got nil error, want error")
+	}
+}
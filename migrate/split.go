@@ -0,0 +1,40 @@
+package migrate
+
+import (
+	"strings"
+
+	"github.com/datastax-ext/astra-go-sdk/internal/cqlscan"
+)
+
+// splitStatements splits CQL into its semicolon separated statements, with
+// comments removed and surrounding whitespace trimmed. Semicolons inside
+// string literals, quoted identifiers and comments do not separate
+// statements. Empty statements are omitted.
+func splitStatements(cql string) []string {
+	var (
+		res []string
+		b   strings.Builder
+	)
+	flush := func() {
+		if s := strings.TrimSpace(b.String()); s != "" {
+			res = append(res, s)
+		}
+		b.Reset()
+	}
+	for i := 0; i < len(cql); {
+		kind, j := cqlscan.Next(cql, i)
+		switch {
+		case kind == cqlscan.LineComment:
+			b.WriteByte('\n')
+		case kind == cqlscan.BlockComment:
+			b.WriteByte(' ')
+		case kind == cqlscan.Other && cql[i] == ';':
+			flush()
+		default:
+			b.WriteString(cql[i:j])
+		}
+		i = j
+	}
+	flush()
+	return res
+}
@@ -2,6 +2,7 @@ package astra
 
 import (
 	"crypto/tls"
+	"io"
 	"time"
 
 	"google.golang.org/grpc"
@@ -24,6 +25,14 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithShutdownGracePeriod sets how long Client.Close waits for queries in
+// flight to complete before canceling them.
+func WithShutdownGracePeriod(gracePeriod time.Duration) ClientOption {
+	return func(c *Client) {
+		c.gracePeriod = gracePeriod
+	}
+}
+
 // WithDefaultKeyspace specifies the default keyspace for client queries.
 func WithDefaultKeyspace(keyspace string) ClientOption {
 	return func(c *Client) {
@@ -31,6 +40,72 @@ func WithDefaultKeyspace(keyspace string) ClientOption {
 	}
 }
 
+// WithDefaultConsistency specifies the default consistency level for client
+// queries that do not specify one.
+func WithDefaultConsistency(consistency Consistency) ClientOption {
+	return func(c *Client) {
+		c.defaultQueryParams.consistency(consistency)
+	}
+}
+
+// WithRetryPolicy specifies the default retry policy for client queries that
+// do not specify one. Only queries marked idempotent are retried. By default,
+// queries are not retried.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.defaultQueryParams.retryPolicy(policy)
+	}
+}
+
+// WithTimestampGenerator specifies a generator of client-side write
+// timestamps, used for all queries and batch queries which do not set one with
+// WithTimestamp. By default, the server assigns write timestamps.
+func WithTimestampGenerator(g TimestampGenerator) ClientOption {
+	return func(c *Client) {
+		c.timestampGenerator = g
+	}
+}
+
+// WithBundleExpiryWarning sets how long before the certificates of the
+// secure connect bundle expire a warning is logged when creating the client.
+// Defaults to 30 days. A bundle whose certificates have already expired fails
+// validation instead.
+func WithBundleExpiryWarning(window time.Duration) ClientOption {
+	return func(c *Client) {
+		c.bundleExpiryWarning = window
+	}
+}
+
+// WithBundleWatch reloads the secure connect bundle every interval, using
+// its credentials for new connections so that certificates can be rotated
+// without recreating the client. Only a bundle specified by path with
+// WithSecureConnectBundle can be watched, and it is only reloaded when the
+// file changes. Queries in flight are unaffected. See Client.ReloadBundle.
+func WithBundleWatch(interval time.Duration) ClientOption {
+	return func(c *Client) {
+		c.bundleWatch = interval
+	}
+}
+
+// WithTokenProvider specifies the provider of the auth tokens sent with
+// requests, replacing the token or table auth credentials passed to the
+// client constructor. A request rejected as unauthenticated is retried once
+// after invalidating its token.
+func WithTokenProvider(p TokenProvider) ClientOption {
+	return func(c *Client) {
+		c.tokenProvider = p
+	}
+}
+
+// WithCredentialsSource specifies a source of the credentials with which to
+// authenticate, replacing the token or table auth credentials passed to the
+// client constructor. Requests rejected as unauthenticated are retried once.
+func WithCredentialsSource(source CredentialsSource) ClientOption {
+	return func(c *Client) {
+		c.tokenProvider = sourceTokenProvider{source: source}
+	}
+}
+
 // WithGRPCConnParams specifies other connection parameters to use for the gRPC
 // connection.
 func WithGRPCConnParams(params *grpc.ConnectParams) ClientOption {
@@ -66,9 +141,50 @@ func WithAstraURI(uri string) StaticTokenConnectConfig {
 }
 
 // WithSecureConnectBundle specifies the secure connect bundle to use for the
-// gRPC connection.
-func WithSecureConnectBundle(path string) StaticTokenConnectConfig {
+// gRPC connection. The address dialed is derived from the bundle as described
+// by BundleOption.
+func WithSecureConnectBundle(path string, opts ...BundleOption) StaticTokenConnectConfig {
+	return func(c *Client) {
+		c.bundlePath = path
+		c.loadBundle = func() (*Bundle, error) {
+			return loadBundleZipFromPath(path, opts)
+		}
+	}
+}
+
+// WithSecureConnectBundleBytes specifies the contents of the secure connect
+// bundle to use for the gRPC connection, e.g. as mounted from a secret. The
+// bundle cannot be watched with WithBundleWatch.
+func WithSecureConnectBundleBytes(b []byte, opts ...BundleOption) StaticTokenConnectConfig {
+	return func(c *Client) {
+		c.bundleFixed = true
+		c.loadBundle = func() (*Bundle, error) {
+			return loadBundleZipFromBytes(b, opts)
+		}
+	}
+}
+
+// WithSecureConnectBundleReader specifies a reader of the secure connect
+// bundle of the given size to use for the gRPC connection. The bundle cannot
+// be watched with WithBundleWatch.
+func WithSecureConnectBundleReader(r io.ReaderAt, size int64, opts ...BundleOption) StaticTokenConnectConfig {
+	return func(c *Client) {
+		c.bundleFixed = true
+		c.loadBundle = func() (*Bundle, error) {
+			return LoadBundleReader(r, size, opts...)
+		}
+	}
+}
+
+// WithSecureConnectBundleEnv specifies an environment variable holding the
+// base64-encoded secure connect bundle to use for the gRPC connection. The
+// variable is read when the client is created, and the bundle cannot be
+// watched with WithBundleWatch.
+func WithSecureConnectBundleEnv(name string, opts ...BundleOption) StaticTokenConnectConfig {
 	return func(c *Client) {
-		c.scbPath = path
+		c.bundleFixed = true
+		c.loadBundle = func() (*Bundle, error) {
+			return loadBundleZipFromEnv(name, opts)
+		}
 	}
 }
@@ -1,6 +1,7 @@
 package astra
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"math/big"
 	"net"
@@ -32,6 +33,8 @@ func valueToProto(value any) (*pb.Value, error) {
 	switch v := value.(type) {
 	case nil:
 		return &pb.Value{Inner: &pb.Value_Null_{Null: &pb.Value_Null{}}}, nil
+	case unsetValue:
+		return &pb.Value{Inner: &pb.Value_Unset_{Unset: &pb.Value_Unset{}}}, nil
 	case int64:
 		return &pb.Value{Inner: &pb.Value_Int{Int: v}}, nil
 	case int32:
@@ -71,6 +74,11 @@ func valueToProto(value any) (*pb.Value, error) {
 		return &pb.Value{Inner: &pb.Value_Uuid{Uuid: &pb.Uuid{Value: v[:]}}}, nil
 	case uuid.UUID:
 		return &pb.Value{Inner: &pb.Value_Uuid{Uuid: &pb.Uuid{Value: v[:]}}}, nil
+	case uuid.NullUUID:
+		if !v.Valid {
+			return &pb.Value{Inner: &pb.Value_Null_{Null: &pb.Value_Null{}}}, nil
+		}
+		return valueToProto(v.UUID)
 	case *time.Time:
 		return &pb.Value{Inner: &pb.Value_Int{Int: v.UnixMilli()}}, nil
 	case time.Time:
@@ -79,11 +87,41 @@ func valueToProto(value any) (*pb.Value, error) {
 		return &pb.Value{Inner: &pb.Value_Varint{Varint: &pb.Varint{
 			Value: encodeBigInt(v),
 		}}}, nil
+	case big.Int:
+		return valueToProto(&v)
 	case *decimal.Decimal:
+		if v == nil {
+			return &pb.Value{Inner: &pb.Value_Null_{Null: &pb.Value_Null{}}}, nil
+		}
 		return encodeDecimal(v)
 	case decimal.Decimal:
 		return encodeDecimal(&v)
+	case UDT:
+		return udtMapToProto(v)
 	default:
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Pointer:
+			if rv.IsNil() {
+				return &pb.Value{Inner: &pb.Value_Null_{Null: &pb.Value_Null{}}}, nil
+			}
+			return valueToProto(rv.Elem().Interface())
+		case reflect.Struct:
+			// Structs such as sql.NullString are written as their value
+			// rather than as UDTs.
+			if vr, ok := v.(driver.Valuer); ok {
+				dv, err := vr.Value()
+				if err != nil {
+					return nil, fmt.Errorf("failed to get value of %T: %w", v, err)
+				}
+				return valueToProto(dv)
+			}
+			res, err := udtStructToProto(rv)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert UDT: %w", err)
+			}
+			return res, nil
+		}
 		res, err := collectionToProto(v)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert collection: %w", err)
@@ -91,11 +129,45 @@ func valueToProto(value any) (*pb.Value, error) {
 		if res != nil {
 			return res, nil
 		}
-		// TODO: add UDT support
 	}
 	return nil, fmt.Errorf("unsupported basic type: %T", value)
 }
 
+// udtMapToProto converts a map of UDT field names to values to a UDT value.
+func udtMapToProto(m UDT) (*pb.Value, error) {
+	if m == nil {
+		return &pb.Value{Inner: &pb.Value_Null_{Null: &pb.Value_Null{}}}, nil
+	}
+	fs := make(map[string]*pb.Value, len(m))
+	for k, v := range m {
+		fv, err := valueToProto(v)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving UDT field %q: %w", k, err)
+		}
+		fs[k] = fv
+	}
+	return &pb.Value{Inner: &pb.Value_Udt{Udt: &pb.UdtValue{Fields: fs}}}, nil
+}
+
+// udtStructToProto converts a struct to a UDT value, using the struct fields
+// mapped by cqlFields as UDT fields.
+func udtStructToProto(v reflect.Value) (*pb.Value, error) {
+	sfs := cqlFields(v.Type())
+	fs := make(map[string]*pb.Value, len(sfs))
+	for _, f := range sfs {
+		var fv any
+		if rv, ok := fieldByIndex(v, f.index); ok {
+			fv = rv.Interface()
+		}
+		pv, err := valueToProto(fv)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving UDT field %q: %w", f.name, err)
+		}
+		fs[f.name] = pv
+	}
+	return &pb.Value{Inner: &pb.Value_Udt{Udt: &pb.UdtValue{Fields: fs}}}, nil
+}
+
 func collectionToProto(value interface{}) (*pb.Value, error) {
 	var els []*pb.Value
 
@@ -164,6 +236,8 @@ func protoToValue(value *pb.Value, spec *pb.TypeSpec) (any, error) {
 		r, err = protoToSlice(value.GetCollection().GetElements(), ts.Set.Element)
 	case *pb.TypeSpec_Tuple_:
 		r, err = tupleProtoToSlice(value.GetCollection().GetElements(), ts.Tuple)
+	case *pb.TypeSpec_Udt_:
+		r, err = udtProtoToMap(value.GetUdt(), ts.Udt)
 	default:
 		err = fmt.Errorf("unsupported type: %s", ts)
 	}
@@ -208,7 +282,6 @@ func basicProtoToValue(value *pb.Value) (any, error) {
 		return dec, nil
 	case *pb.Value_Varint:
 		return decodeBigInt(v.Varint.Value), nil
-		// TODO: add UDT support
 	}
 	return nil, fmt.Errorf("unsupported value type: %T, value: %+v", value.GetInner(), value.GetInner())
 }
@@ -299,6 +372,30 @@ func tupleProtoToSlice(values []*pb.Value, spec *pb.TypeSpec_Tuple) (any, error)
 	return s, nil
 }
 
+// udtProtoToMap converts a UDT value to a map of UDT field names to values.
+// Fields in spec which are missing from value are set to nil.
+func udtProtoToMap(value *pb.UdtValue, spec *pb.TypeSpec_Udt) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	specs := spec.GetFields()
+	m := make(map[string]any, len(specs))
+	for name, fs := range specs {
+		fv, ok := value.GetFields()[name]
+		if !ok {
+			m[name] = nil
+			continue
+		}
+		v, err := protoToValue(fv, fs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert UDT field %q: %w", name, err)
+		}
+		m[name] = v
+	}
+	return m, nil
+}
+
 func encodeDecimal(d *decimal.Decimal) (*pb.Value, error) {
 	return &pb.Value{Inner: &pb.Value_Decimal{Decimal: &pb.Decimal{
 		Scale: uint32(-d.Exponent()),
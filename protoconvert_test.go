@@ -1,6 +1,7 @@
 package astra
 
 import (
+	"database/sql"
 	"math/big"
 	"net"
 	"testing"
@@ -250,6 +251,36 @@ func TestProtosToValue(t *testing.T) {
 	}
 }
 
+func TestValueToProto_scalarStructs(t *testing.T) {
+	id := uuid.MustParse("12345678-1234-5678-1234-567812345678")
+	null := &pb.Value{Inner: &pb.Value_Null_{Null: &pb.Value_Null{}}}
+	tests := []struct {
+		name string
+		in   any
+		want *pb.Value
+	}{
+		{"valid NullUUID", uuid.NullUUID{UUID: id, Valid: true}, &pb.Value{Inner: &pb.Value_Uuid{Uuid: &pb.Uuid{Value: id[:]}}}},
+		{"null NullUUID", uuid.NullUUID{}, null},
+		{"valid NullString", sql.NullString{String: "foo", Valid: true}, &pb.Value{Inner: &pb.Value_String_{String_: "foo"}}},
+		{"null NullString", sql.NullString{}, null},
+		{"valid NullInt64", sql.NullInt64{Int64: 64, Valid: true}, &pb.Value{Inner: &pb.Value_Int{Int: 64}}},
+		{"null NullInt64", sql.NullInt64{}, null},
+		{"big.Int value", *big.NewInt(16), &pb.Value{Inner: &pb.Value_Varint{Varint: &pb.Varint{Value: encodeBigInt(big.NewInt(16))}}}},
+		{"nil decimal pointer", (*decimal.Decimal)(nil), null},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := valueToProto(tt.in)
+			if err != nil {
+				t.Fatalf("valueToProto(%v) unexpected error: %v", tt.in, err)
+			}
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("valueToProto(%v) unexpected difference (-want +got):\n%s", tt.in, diff)
+			}
+		})
+	}
+}
+
 func TestNilUUIDPointer(t *testing.T) {
 	var src *uuid.UUID
 	got, err := valueToProto(src)
@@ -261,3 +292,159 @@ func TestNilUUIDPointer(t *testing.T) {
 		t.Fatalf("valueToProto(%v) unexpected difference (-want +got):\n%s", src, diff)
 	}
 }
+
+type testAddress struct {
+	Street  string `cql:"street"`
+	ZipCode int    `cql:"zip"`
+	Ignored string `cql:"-"`
+}
+
+func TestValueToProto_udt(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want *pb.Value
+	}{
+		{
+			name: "struct",
+			in:   testAddress{Street: "Main St", ZipCode: 12345, Ignored: "foo"},
+			want: &pb.Value{Inner: &pb.Value_Udt{Udt: &pb.UdtValue{Fields: map[string]*pb.Value{
+				"street": {Inner: &pb.Value_String_{String_: "Main St"}},
+				"zip":    {Inner: &pb.Value_Int{Int: 12345}},
+			}}}},
+		},
+		{
+			name: "struct pointer",
+			in:   &testAddress{Street: "Main St"},
+			want: &pb.Value{Inner: &pb.Value_Udt{Udt: &pb.UdtValue{Fields: map[string]*pb.Value{
+				"street": {Inner: &pb.Value_String_{String_: "Main St"}},
+				"zip":    {Inner: &pb.Value_Int{Int: 0}},
+			}}}},
+		},
+		{
+			name: "nil struct pointer",
+			in:   (*testAddress)(nil),
+			want: &pb.Value{Inner: &pb.Value_Null_{Null: &pb.Value_Null{}}},
+		},
+		{
+			name: "UDT map",
+			in:   UDT{"street": "Main St", "zip": nil},
+			want: &pb.Value{Inner: &pb.Value_Udt{Udt: &pb.UdtValue{Fields: map[string]*pb.Value{
+				"street": {Inner: &pb.Value_String_{String_: "Main St"}},
+				"zip":    {Inner: &pb.Value_Null_{Null: &pb.Value_Null{}}},
+			}}}},
+		},
+		{
+			name: "map[string]any",
+			in:   map[string]any{"street": "Main St"},
+			want: &pb.Value{Inner: &pb.Value_Collection{Collection: &pb.Collection{Elements: []*pb.Value{
+				{Inner: &pb.Value_String_{String_: "street"}},
+				{Inner: &pb.Value_String_{String_: "Main St"}},
+			}}}},
+		},
+		{
+			name: "list of structs",
+			in:   []testAddress{{Street: "Main St", ZipCode: 1}},
+			want: &pb.Value{Inner: &pb.Value_Collection{Collection: &pb.Collection{Elements: []*pb.Value{
+				{Inner: &pb.Value_Udt{Udt: &pb.UdtValue{Fields: map[string]*pb.Value{
+					"street": {Inner: &pb.Value_String_{String_: "Main St"}},
+					"zip":    {Inner: &pb.Value_Int{Int: 1}},
+				}}}},
+			}}}},
+		},
+		{
+			name: "nested struct",
+			in: struct {
+				Name string
+				Home testAddress
+			}{Name: "Alice", Home: testAddress{Street: "Main St", ZipCode: 1}},
+			want: &pb.Value{Inner: &pb.Value_Udt{Udt: &pb.UdtValue{Fields: map[string]*pb.Value{
+				"name": {Inner: &pb.Value_String_{String_: "Alice"}},
+				"home": {Inner: &pb.Value_Udt{Udt: &pb.UdtValue{Fields: map[string]*pb.Value{
+					"street": {Inner: &pb.Value_String_{String_: "Main St"}},
+					"zip":    {Inner: &pb.Value_Int{Int: 1}},
+				}}}},
+			}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := valueToProto(tt.in)
+			if err != nil {
+				t.Fatalf("valueToProto(%v) unexpected error: %v", tt.in, err)
+			}
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("valueToProto(%v) unexpected difference (-want +got):\n%s", tt.in, diff)
+			}
+		})
+	}
+}
+
+func TestProtoToValue_udt(t *testing.T) {
+	udtSpec := &pb.TypeSpec{Spec: &pb.TypeSpec_Udt_{Udt: &pb.TypeSpec_Udt{Fields: map[string]*pb.TypeSpec{
+		"street": {Spec: &pb.TypeSpec_Basic_{Basic: pb.TypeSpec_TEXT}},
+		"zip":    {Spec: &pb.TypeSpec_Basic_{Basic: pb.TypeSpec_INT}},
+	}}}}
+	udt := &pb.Value{Inner: &pb.Value_Udt{Udt: &pb.UdtValue{Fields: map[string]*pb.Value{
+		"street": {Inner: &pb.Value_String_{String_: "Main St"}},
+	}}}}
+	wantUDT := map[string]any{"street": "Main St", "zip": nil}
+
+	tests := []struct {
+		name string
+		in   *pb.Value
+		spec *pb.TypeSpec
+		want any
+	}{
+		{
+			name: "udt",
+			in:   udt,
+			spec: udtSpec,
+			want: wantUDT,
+		},
+		{
+			name: "null udt",
+			in:   &pb.Value{Inner: &pb.Value_Null_{Null: &pb.Value_Null{}}},
+			spec: udtSpec,
+			want: nil,
+		},
+		{
+			name: "list of udts",
+			in:   &pb.Value{Inner: &pb.Value_Collection{Collection: &pb.Collection{Elements: []*pb.Value{udt}}}},
+			spec: &pb.TypeSpec{Spec: &pb.TypeSpec_List_{List: &pb.TypeSpec_List{Element: udtSpec}}},
+			want: []map[string]any{wantUDT},
+		},
+		{
+			name: "map of udts",
+			in: &pb.Value{Inner: &pb.Value_Collection{Collection: &pb.Collection{Elements: []*pb.Value{
+				{Inner: &pb.Value_String_{String_: "home"}}, udt,
+			}}}},
+			spec: &pb.TypeSpec{Spec: &pb.TypeSpec_Map_{Map: &pb.TypeSpec_Map{
+				Key:   &pb.TypeSpec{Spec: &pb.TypeSpec_Basic_{Basic: pb.TypeSpec_TEXT}},
+				Value: udtSpec,
+			}}},
+			want: map[string]map[string]any{"home": wantUDT},
+		},
+		{
+			name: "tuple with udt",
+			in: &pb.Value{Inner: &pb.Value_Collection{Collection: &pb.Collection{Elements: []*pb.Value{
+				{Inner: &pb.Value_Int{Int: 1}}, udt,
+			}}}},
+			spec: &pb.TypeSpec{Spec: &pb.TypeSpec_Tuple_{Tuple: &pb.TypeSpec_Tuple{Elements: []*pb.TypeSpec{
+				{Spec: &pb.TypeSpec_Basic_{Basic: pb.TypeSpec_INT}}, udtSpec,
+			}}}},
+			want: []any{int64(1), wantUDT},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := protoToValue(tt.in, tt.spec)
+			if err != nil {
+				t.Fatalf("protoToValue(%v) unexpected error: %v", tt.in, err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("protoToValue(%v) unexpected difference (-want +got):\n%s", tt.in, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package qb
+
+import (
+	"strings"
+	"time"
+
+	"github.com/datastax-ext/astra-go-sdk"
+)
+
+// DeleteBuilder builds a DELETE statement. Use Delete to create a
+// DeleteBuilder.
+type DeleteBuilder struct {
+	table    string
+	columns  []string
+	where    []Cond
+	ifConds  []Cond
+	ifExists bool
+	using
+}
+
+// Delete creates a new DeleteBuilder deleting from table.
+func Delete(table string) *DeleteBuilder {
+	return &DeleteBuilder{table: table}
+}
+
+// Columns sets the columns, or collection elements such as "tags['a']", to
+// delete. By default, whole rows are deleted.
+func (s *DeleteBuilder) Columns(columns ...string) *DeleteBuilder {
+	s.columns = append(s.columns, columns...)
+	return s
+}
+
+// Where adds conditions to the WHERE clause.
+func (s *DeleteBuilder) Where(conds ...Cond) *DeleteBuilder {
+	s.where = append(s.where, conds...)
+	return s
+}
+
+// If adds conditions to the IF clause, making the statement a lightweight
+// transaction. Execute it with astra.Query.ExecCAS.
+func (s *DeleteBuilder) If(conds ...Cond) *DeleteBuilder {
+	s.ifConds = append(s.ifConds, conds...)
+	return s
+}
+
+// IfExists makes the statement a lightweight transaction which only deletes
+// the row if it exists. Execute it with astra.Query.ExecCAS.
+func (s *DeleteBuilder) IfExists() *DeleteBuilder {
+	s.ifExists = true
+	return s
+}
+
+// Timestamp sets the timestamp of the deletion. It cannot be combined with If
+// or IfExists.
+func (s *DeleteBuilder) Timestamp(t time.Time) *DeleteBuilder {
+	s.timestamp = &t
+	return s
+}
+
+// ToCQL returns the CQL of the statement and the values of its bind markers.
+// Call Err to check that the statement is valid.
+func (s *DeleteBuilder) ToCQL() (string, []any) {
+	var b strings.Builder
+	b.WriteString("DELETE ")
+	if len(s.columns) > 0 {
+		b.WriteString(strings.Join(s.columns, ", "))
+		b.WriteString(" ")
+	}
+	b.WriteString("FROM ")
+	b.WriteString(s.table)
+	s.using.write(&b)
+	values := writeConds(&b, "WHERE", s.where, nil)
+	if s.ifExists {
+		b.WriteString(" IF EXISTS")
+	} else {
+		values = writeConds(&b, "IF", s.ifConds, values)
+	}
+	return b.String(), values
+}
+
+// Err returns an error if the statement is invalid, e.g. if it sets a
+// timestamp and is conditional.
+func (s *DeleteBuilder) Err() error {
+	if err := checkConds(s.ifExists, s.ifConds); err != nil {
+		return err
+	}
+	return s.using.check(s.ifExists || len(s.ifConds) > 0)
+}
+
+// Query creates a new astra.Query executing the statement with c. If the
+// statement is invalid, executing the query returns the error reported by Err.
+func (s *DeleteBuilder) Query(c *astra.Client) *astra.Query {
+	cql, values := s.ToCQL()
+	return query(c, cql, values, s.Err())
+}
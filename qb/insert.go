@@ -0,0 +1,83 @@
+package qb
+
+import (
+	"strings"
+	"time"
+
+	"github.com/datastax-ext/astra-go-sdk"
+)
+
+// InsertBuilder builds an INSERT statement. Use Insert to create an
+// InsertBuilder.
+type InsertBuilder struct {
+	table       string
+	columns     []string
+	values      []any
+	ifNotExists bool
+	using
+}
+
+// Insert creates a new InsertBuilder inserting into table.
+func Insert(table string) *InsertBuilder {
+	return &InsertBuilder{table: table}
+}
+
+// Value adds a column and the value to insert into it.
+func (s *InsertBuilder) Value(column string, value any) *InsertBuilder {
+	s.columns = append(s.columns, column)
+	s.values = append(s.values, value)
+	return s
+}
+
+// IfNotExists makes the statement a lightweight transaction which only
+// inserts the row if it does not already exist. Execute it with
+// astra.Query.ExecCAS.
+func (s *InsertBuilder) IfNotExists() *InsertBuilder {
+	s.ifNotExists = true
+	return s
+}
+
+// TTL sets the time to live of the inserted values, rounded down to the
+// second. A TTL under one second is invalid.
+func (s *InsertBuilder) TTL(ttl time.Duration) *InsertBuilder {
+	s.ttl = ttl
+	return s
+}
+
+// Timestamp sets the write timestamp of the inserted values. It cannot be
+// combined with IfNotExists.
+func (s *InsertBuilder) Timestamp(t time.Time) *InsertBuilder {
+	s.timestamp = &t
+	return s
+}
+
+// ToCQL returns the CQL of the statement and the values of its bind markers.
+// Call Err to check that the statement is valid.
+func (s *InsertBuilder) ToCQL() (string, []any) {
+	var b strings.Builder
+	b.WriteString("INSERT INTO ")
+	b.WriteString(s.table)
+	b.WriteString(" (")
+	b.WriteString(strings.Join(s.columns, ", "))
+	b.WriteString(") VALUES (")
+	b.WriteString(markers(len(s.values)))
+	b.WriteString(")")
+	if s.ifNotExists {
+		b.WriteString(" IF NOT EXISTS")
+	}
+	s.using.write(&b)
+	return b.String(), append([]any(nil), s.values...)
+}
+
+// Err returns an error if the statement is invalid, e.g. if it sets a
+// timestamp and is conditional, or a TTL under one second.
+func (s *InsertBuilder) Err() error {
+	return s.using.check(s.ifNotExists)
+}
+
+// Query creates a new astra.Query executing the statement with c. If the
+// statement is invalid, executing the query returns the error reported by Err.
+func (s *InsertBuilder) Query(c *astra.Client) *astra.Query {
+	cql, values := s.ToCQL()
+	return query(c, cql, values, s.Err())
+}
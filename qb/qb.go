@@ -0,0 +1,188 @@
+// Package qb provides fluent builders for CQL statements which produce
+// executable astra.Query values, keeping bind markers and their values in
+// order.
+//
+//	q := qb.Select("users").
+//	    Columns("id", "name").
+//	    Where(qb.Eq("id", id)).
+//	    Query(c)
+//	rows, err := q.Exec()
+//
+// Table and column names are written as given, so names which require quoting
+// must be passed quoted, e.g. `"userName"`.
+package qb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/datastax-ext/astra-go-sdk"
+	"github.com/datastax-ext/astra-go-sdk/internal/hooks"
+)
+
+// Cond is a condition of a WHERE or IF clause.
+type Cond struct {
+	expr   string
+	values []any
+}
+
+func compare(column, op string, value any) Cond {
+	return Cond{expr: fmt.Sprintf("%s %s ?", column, op), values: []any{value}}
+}
+
+// Eq returns the condition column = value.
+func Eq(column string, value any) Cond { return compare(column, "=", value) }
+
+// Ne returns the condition column != value. It is only valid in IF clauses.
+func Ne(column string, value any) Cond { return compare(column, "!=", value) }
+
+// Lt returns the condition column < value.
+func Lt(column string, value any) Cond { return compare(column, "<", value) }
+
+// Le returns the condition column <= value.
+func Le(column string, value any) Cond { return compare(column, "<=", value) }
+
+// Gt returns the condition column > value.
+func Gt(column string, value any) Cond { return compare(column, ">", value) }
+
+// Ge returns the condition column >= value.
+func Ge(column string, value any) Cond { return compare(column, ">=", value) }
+
+// Contains returns the condition column CONTAINS value, for collection
+// columns.
+func Contains(column string, value any) Cond { return compare(column, "CONTAINS", value) }
+
+// ContainsKey returns the condition column CONTAINS KEY key, for map columns.
+func ContainsKey(column string, key any) Cond { return compare(column, "CONTAINS KEY", key) }
+
+// In returns the condition column IN (values...).
+func In(column string, values ...any) Cond {
+	return Cond{
+		expr:   fmt.Sprintf("%s IN (%s)", column, markers(len(values))),
+		values: values,
+	}
+}
+
+// Raw returns a condition with the given CQL expression and values for its
+// bind markers, for conditions not covered by the other functions.
+func Raw(expr string, values ...any) Cond {
+	return Cond{expr: expr, values: values}
+}
+
+// TokenExpr is the token of a partition key, for token range conditions.
+type TokenExpr struct {
+	columns []string
+}
+
+// Token returns the token of the partition key made up of columns, in order.
+//
+//	qb.Select("users").Where(qb.Token("id").Gt(lastID))
+func Token(columns ...string) TokenExpr {
+	return TokenExpr{columns: columns}
+}
+
+func (t TokenExpr) compare(op string, values []any) Cond {
+	return Cond{
+		expr:   fmt.Sprintf("token(%s) %s token(%s)", strings.Join(t.columns, ", "), op, markers(len(values))),
+		values: values,
+	}
+}
+
+// Eq returns the condition token(columns) = token(values).
+func (t TokenExpr) Eq(values ...any) Cond { return t.compare("=", values) }
+
+// Lt returns the condition token(columns) < token(values).
+func (t TokenExpr) Lt(values ...any) Cond { return t.compare("<", values) }
+
+// Le returns the condition token(columns) <= token(values).
+func (t TokenExpr) Le(values ...any) Cond { return t.compare("<=", values) }
+
+// Gt returns the condition token(columns) > token(values).
+func (t TokenExpr) Gt(values ...any) Cond { return t.compare(">", values) }
+
+// Ge returns the condition token(columns) >= token(values).
+func (t TokenExpr) Ge(values ...any) Cond { return t.compare(">=", values) }
+
+// markers returns n comma separated bind markers.
+func markers(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strings.Repeat("?, ", n-1) + "?"
+}
+
+// query creates a new astra.Query executing cql with c which, if err is not
+// nil, fails with err when executed.
+func query(c *astra.Client, cql string, values []any, err error) *astra.Query {
+	q := c.Query(cql, values...)
+	if err != nil {
+		hooks.SetQueryErr(q, fmt.Errorf("failed to build query: %w", err))
+	}
+	return q
+}
+
+// writeConds writes the conditions to b, preceded by keyword, and appends
+// their values to values.
+func writeConds(b *strings.Builder, keyword string, conds []Cond, values []any) []any {
+	if len(conds) == 0 {
+		return values
+	}
+	b.WriteString(" ")
+	b.WriteString(keyword)
+	b.WriteString(" ")
+	for i, c := range conds {
+		if i > 0 {
+			b.WriteString(" AND ")
+		}
+		b.WriteString(c.expr)
+		values = append(values, c.values...)
+	}
+	return values
+}
+
+// using holds the USING clause of a write statement.
+type using struct {
+	ttl       time.Duration
+	timestamp *time.Time
+}
+
+func (u *using) write(b *strings.Builder) {
+	var opts []string
+	if u.ttl > 0 {
+		opts = append(opts, fmt.Sprintf("TTL %d", int64(u.ttl/time.Second)))
+	}
+	if u.timestamp != nil {
+		opts = append(opts, fmt.Sprintf("TIMESTAMP %d", u.timestamp.UnixMicro()))
+	}
+	if len(opts) > 0 {
+		b.WriteString(" USING ")
+		b.WriteString(strings.Join(opts, " AND "))
+	}
+}
+
+// check returns an error if the USING clause is invalid for the statement,
+// which is conditional if it has an IF clause. A TTL under one second would be
+// written as TTL 0, meaning the values never expire.
+func (u *using) check(conditional bool) error {
+	if u.ttl < 0 {
+		return fmt.Errorf("TTL %v is negative", u.ttl)
+	}
+	if u.ttl > 0 && u.ttl < time.Second {
+		return fmt.Errorf("TTL %v is less than one second", u.ttl)
+	}
+	if u.timestamp != nil && conditional {
+		return errors.New("cannot set the timestamp of a conditional statement")
+	}
+	return nil
+}
+
+// checkConds returns an error if both IF EXISTS and IF conditions are set, as
+// only one IF clause can be written.
+func checkConds(ifExists bool, ifConds []Cond) error {
+	if ifExists && len(ifConds) > 0 {
+		return errors.New("cannot combine IfExists with If conditions")
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package qb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/datastax-ext/astra-go-sdk"
+	"github.com/google/go-cmp/cmp"
+)
+
+type builder interface {
+	ToCQL() (string, []any)
+}
+
+func TestToCQL(t *testing.T) {
+	ts := time.UnixMicro(1659312000000000)
+
+	tests := []struct {
+		name       string
+		b          builder
+		wantCQL    string
+		wantValues []any
+	}{
+		{
+			name:    "select all",
+			b:       Select("ks.users"),
+			wantCQL: `SELECT * FROM ks.users`,
+		},
+		{
+			name: "select",
+			b: Select("events").
+				Columns("id", "ts", "payload").
+				Where(Eq("day", "mon"), In("kind", "a", "b"), Ge("ts", 10)).
+				OrderBy("ts", Desc).
+				PerPartitionLimit(5).
+				Limit(100).
+				AllowFiltering(),
+			wantCQL:    `SELECT id, ts, payload FROM events WHERE day = ? AND kind IN (?, ?) AND ts >= ? ORDER BY ts DESC PER PARTITION LIMIT 5 LIMIT 100 ALLOW FILTERING`,
+			wantValues: []any{"mon", "a", "b", 10},
+		},
+		{
+			name:       "select token range",
+			b:          Select("users").Distinct().Columns("id").Where(Token("id").Gt(1), Token("id").Le(2)),
+			wantCQL:    `SELECT DISTINCT id FROM users WHERE token(id) > token(?) AND token(id) <= token(?)`,
+			wantValues: []any{1, 2},
+		},
+		{
+			name: "insert",
+			b: Insert("users").
+				Value("id", 1).
+				Value("name", "Alice").
+				IfNotExists().
+				TTL(time.Hour),
+			wantCQL:    `INSERT INTO users (id, name) VALUES (?, ?) IF NOT EXISTS USING TTL 3600`,
+			wantValues: []any{1, "Alice"},
+		},
+		{
+			name:       "insert timestamp",
+			b:          Insert("users").Value("id", 1).TTL(time.Hour).Timestamp(ts),
+			wantCQL:    `INSERT INTO users (id) VALUES (?) USING TTL 3600 AND TIMESTAMP 1659312000000000`,
+			wantValues: []any{1},
+		},
+		{
+			name: "update",
+			b: Update("users").
+				TTL(time.Minute).
+				Set("name", "Alice").
+				Add("tags", []string{"new"}).
+				Remove("roles", []string{"admin"}).
+				Prepend("history", []string{"login"}).
+				SetKey("prefs", "theme", "dark").
+				Where(Eq("id", 1)).
+				If(Eq("version", 2), Ne("locked", true)),
+			wantCQL:    `UPDATE users USING TTL 60 SET name = ?, tags = tags + ?, roles = roles - ?, history = ? + history, prefs[?] = ? WHERE id = ? IF version = ? AND locked != ?`,
+			wantValues: []any{"Alice", []string{"new"}, []string{"admin"}, []string{"login"}, "theme", "dark", 1, 2, true},
+		},
+		{
+			name:       "update counter",
+			b:          Update("page_views").Add("views", 1).Where(Eq("page", "/")).IfExists(),
+			wantCQL:    `UPDATE page_views SET views = views + ? WHERE page = ? IF EXISTS`,
+			wantValues: []any{1, "/"},
+		},
+		{
+			name:       "delete",
+			b:          Delete("users").Where(Eq("id", 1)).IfExists(),
+			wantCQL:    `DELETE FROM users WHERE id = ? IF EXISTS`,
+			wantValues: []any{1},
+		},
+		{
+			name: "delete columns",
+			b: Delete("users").
+				Columns("email", "prefs['theme']").
+				Timestamp(ts).
+				Where(Eq("id", 1)).
+				If(Raw("email = ?", "a@example.com")),
+			wantCQL:    `DELETE email, prefs['theme'] FROM users USING TIMESTAMP 1659312000000000 WHERE id = ? IF email = ?`,
+			wantValues: []any{1, "a@example.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cql, values := tt.b.ToCQL()
+			if cql != tt.wantCQL {
+				t.Errorf("ToCQL() got CQL:\n%s\nwant:\n%s", cql, tt.wantCQL)
+			}
+			if diff := cmp.Diff(tt.wantValues, values); diff != "" {
+				t.Errorf("ToCQL() values mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestErr(t *testing.T) {
+	ts := time.UnixMicro(1659312000000000)
+
+	tests := []struct {
+		name    string
+		b       interface{ Err() error }
+		wantErr bool
+	}{
+		{name: "insert", b: Insert("users").Value("id", 1).Timestamp(ts)},
+		{name: "insert timestamp if not exists", b: Insert("users").Value("id", 1).IfNotExists().Timestamp(ts), wantErr: true},
+		{name: "update", b: Update("users").Set("name", "Alice").Where(Eq("id", 1))},
+		{name: "update no assignments", b: Update("users").Where(Eq("id", 1)), wantErr: true},
+		{name: "update timestamp if", b: Update("users").Set("name", "Alice").Timestamp(ts).If(Eq("version", 1)), wantErr: true},
+		{name: "delete timestamp if exists", b: Delete("users").Where(Eq("id", 1)).Timestamp(ts).IfExists(), wantErr: true},
+		{name: "insert sub-second TTL", b: Insert("users").Value("id", 1).TTL(500 * time.Millisecond), wantErr: true},
+		{name: "insert negative TTL", b: Insert("users").Value("id", 1).TTL(-time.Hour), wantErr: true},
+		{name: "update sub-second TTL", b: Update("users").Set("name", "Alice").TTL(time.Millisecond), wantErr: true},
+		{name: "update if exists and if", b: Update("users").Set("name", "Alice").IfExists().If(Eq("version", 1)), wantErr: true},
+		{name: "delete if exists and if", b: Delete("users").Where(Eq("id", 1)).IfExists().If(Eq("version", 1)), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.b.Err(); (err != nil) != tt.wantErr {
+				t.Errorf("Err() got %v, want error: %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuery_err(t *testing.T) {
+	c := &astra.Client{}
+	if _, err := Update("users").Where(Eq("id", 1)).Query(c).ExecResult(); err == nil {
+		t.Errorf("ExecResult() of update without assignments got nil error, want error")
+	}
+}
@@ -0,0 +1,120 @@
+package qb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/datastax-ext/astra-go-sdk"
+)
+
+// Order is the order of an ORDER BY clause.
+type Order bool
+
+// Orders for SelectBuilder.OrderBy.
+const (
+	Asc  Order = false
+	Desc Order = true
+)
+
+// SelectBuilder builds a SELECT statement. Use Select to create a
+// SelectBuilder.
+type SelectBuilder struct {
+	table             string
+	columns           []string
+	distinct          bool
+	where             []Cond
+	orderBy           []string
+	limit             int
+	perPartitionLimit int
+	allowFiltering    bool
+}
+
+// Select creates a new SelectBuilder selecting from table.
+func Select(table string) *SelectBuilder {
+	return &SelectBuilder{table: table}
+}
+
+// Columns sets the columns, or other selectors such as "COUNT(*)", to select.
+// By default, all columns are selected.
+func (s *SelectBuilder) Columns(columns ...string) *SelectBuilder {
+	s.columns = append(s.columns, columns...)
+	return s
+}
+
+// Distinct selects only distinct partition keys.
+func (s *SelectBuilder) Distinct() *SelectBuilder {
+	s.distinct = true
+	return s
+}
+
+// Where adds conditions to the WHERE clause.
+func (s *SelectBuilder) Where(conds ...Cond) *SelectBuilder {
+	s.where = append(s.where, conds...)
+	return s
+}
+
+// OrderBy adds a clustering column to the ORDER BY clause.
+func (s *SelectBuilder) OrderBy(column string, order Order) *SelectBuilder {
+	if order == Desc {
+		column += " DESC"
+	} else {
+		column += " ASC"
+	}
+	s.orderBy = append(s.orderBy, column)
+	return s
+}
+
+// Limit sets the maximum number of rows to select.
+func (s *SelectBuilder) Limit(n int) *SelectBuilder {
+	s.limit = n
+	return s
+}
+
+// PerPartitionLimit sets the maximum number of rows to select per partition.
+func (s *SelectBuilder) PerPartitionLimit(n int) *SelectBuilder {
+	s.perPartitionLimit = n
+	return s
+}
+
+// AllowFiltering allows conditions which require filtering on the server.
+func (s *SelectBuilder) AllowFiltering() *SelectBuilder {
+	s.allowFiltering = true
+	return s
+}
+
+// ToCQL returns the CQL of the statement and the values of its bind markers.
+func (s *SelectBuilder) ToCQL() (string, []any) {
+	var b strings.Builder
+	b.WriteString("SELECT ")
+	if s.distinct {
+		b.WriteString("DISTINCT ")
+	}
+	if len(s.columns) == 0 {
+		b.WriteString("*")
+	} else {
+		b.WriteString(strings.Join(s.columns, ", "))
+	}
+	b.WriteString(" FROM ")
+	b.WriteString(s.table)
+	values := writeConds(&b, "WHERE", s.where, nil)
+	if len(s.orderBy) > 0 {
+		b.WriteString(" ORDER BY ")
+		b.WriteString(strings.Join(s.orderBy, ", "))
+	}
+	if s.perPartitionLimit > 0 {
+		fmt.Fprintf(&b, " PER PARTITION LIMIT %d", s.perPartitionLimit)
+	}
+	if s.limit > 0 {
+		fmt.Fprintf(&b, " LIMIT %d", s.limit)
+	}
+	if s.allowFiltering {
+		b.WriteString(" ALLOW FILTERING")
+	}
+	return b.String(), values
+}
+
+// Query creates a new astra.Query executing the statement with c.
+func (s *SelectBuilder) Query(c *astra.Client) *astra.Query {
+	cql, values := s.ToCQL()
+	return c.Query(cql, values...)
+}
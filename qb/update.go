@@ -0,0 +1,138 @@
+package qb
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/datastax-ext/astra-go-sdk"
+)
+
+// UpdateBuilder builds an UPDATE statement. Use Update to create an
+// UpdateBuilder.
+type UpdateBuilder struct {
+	table    string
+	set      []Cond
+	where    []Cond
+	ifConds  []Cond
+	ifExists bool
+	using
+}
+
+// Update creates a new UpdateBuilder updating table.
+func Update(table string) *UpdateBuilder {
+	return &UpdateBuilder{table: table}
+}
+
+// Set adds the assignment column = value.
+func (s *UpdateBuilder) Set(column string, value any) *UpdateBuilder {
+	return s.assign(column+" = ?", value)
+}
+
+// SetKey adds the assignment column[key] = value, setting an element of a
+// map column, or of a list column by index.
+func (s *UpdateBuilder) SetKey(column string, key, value any) *UpdateBuilder {
+	return s.assign(column+"[?] = ?", key, value)
+}
+
+// Add adds the assignment column = column + value, which increments a counter
+// column, appends to a list column, or adds elements to a set or map column.
+func (s *UpdateBuilder) Add(column string, value any) *UpdateBuilder {
+	return s.assign(column+" = "+column+" + ?", value)
+}
+
+// Remove adds the assignment column = column - value, which decrements a
+// counter column, or removes elements from a list, set or map column. To
+// remove map entries, value must be a set of keys.
+func (s *UpdateBuilder) Remove(column string, value any) *UpdateBuilder {
+	return s.assign(column+" = "+column+" - ?", value)
+}
+
+// Prepend adds the assignment column = value + column, prepending to a list
+// column.
+func (s *UpdateBuilder) Prepend(column string, value any) *UpdateBuilder {
+	return s.assign(column+" = ? + "+column, value)
+}
+
+func (s *UpdateBuilder) assign(expr string, values ...any) *UpdateBuilder {
+	s.set = append(s.set, Cond{expr: expr, values: values})
+	return s
+}
+
+// Where adds conditions to the WHERE clause.
+func (s *UpdateBuilder) Where(conds ...Cond) *UpdateBuilder {
+	s.where = append(s.where, conds...)
+	return s
+}
+
+// If adds conditions to the IF clause, making the statement a lightweight
+// transaction. Execute it with astra.Query.ExecCAS.
+func (s *UpdateBuilder) If(conds ...Cond) *UpdateBuilder {
+	s.ifConds = append(s.ifConds, conds...)
+	return s
+}
+
+// IfExists makes the statement a lightweight transaction which only updates
+// the row if it exists. Execute it with astra.Query.ExecCAS.
+func (s *UpdateBuilder) IfExists() *UpdateBuilder {
+	s.ifExists = true
+	return s
+}
+
+// TTL sets the time to live of the updated values, rounded down to the
+// second. A TTL under one second is invalid.
+func (s *UpdateBuilder) TTL(ttl time.Duration) *UpdateBuilder {
+	s.ttl = ttl
+	return s
+}
+
+// Timestamp sets the write timestamp of the updated values. It cannot be
+// combined with If or IfExists.
+func (s *UpdateBuilder) Timestamp(t time.Time) *UpdateBuilder {
+	s.timestamp = &t
+	return s
+}
+
+// ToCQL returns the CQL of the statement and the values of its bind markers.
+// Call Err to check that the statement is valid.
+func (s *UpdateBuilder) ToCQL() (string, []any) {
+	var b strings.Builder
+	b.WriteString("UPDATE ")
+	b.WriteString(s.table)
+	s.using.write(&b)
+	var values []any
+	b.WriteString(" SET ")
+	for i, a := range s.set {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(a.expr)
+		values = append(values, a.values...)
+	}
+	values = writeConds(&b, "WHERE", s.where, values)
+	if s.ifExists {
+		b.WriteString(" IF EXISTS")
+	} else {
+		values = writeConds(&b, "IF", s.ifConds, values)
+	}
+	return b.String(), values
+}
+
+// Err returns an error if the statement is invalid, e.g. if it has no
+// assignments.
+func (s *UpdateBuilder) Err() error {
+	if len(s.set) == 0 {
+		return errors.New("update has no assignments")
+	}
+	if err := checkConds(s.ifExists, s.ifConds); err != nil {
+		return err
+	}
+	return s.using.check(s.ifExists || len(s.ifConds) > 0)
+}
+
+// Query creates a new astra.Query executing the statement with c. If the
+// statement is invalid, executing the query returns the error reported by Err.
+func (s *UpdateBuilder) Query(c *astra.Client) *astra.Query {
+	cql, values := s.ToCQL()
+	return query(c, cql, values, s.Err())
+}
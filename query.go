@@ -1,12 +1,21 @@
 package astra
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/datastax-ext/astra-go-sdk/internal/hooks"
 	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
+func init() {
+	hooks.SetQueryErr = func(q any, err error) {
+		q.(*Query).err = err
+	}
+}
+
 type BatchType uint8
 
 // Batch types for BatchQuery.
@@ -18,29 +27,98 @@ const (
 )
 
 type params struct {
-	keyspace string
+	keyspace          string
+	pageSize          int32
+	consistency       Consistency
+	serialConsistency Consistency
+	retryPolicy       RetryPolicy
+	idempotent        bool
+	tracing           bool
+	// timestamp is the write timestamp in microseconds since the epoch, or
+	// nil to use the client timestamp generator or the server time.
+	timestamp *int64
+}
+
+// withDefaults returns a copy of p in which any unset fields are taken from
+// defaults.
+func (p *params) withDefaults(defaults *params) *params {
+	if p == nil {
+		return defaults
+	}
+	if defaults == nil {
+		return p
+	}
+	res := *p
+	if res.keyspace == "" {
+		res.keyspace = defaults.keyspace
+	}
+	if res.pageSize == 0 {
+		res.pageSize = defaults.pageSize
+	}
+	if res.consistency == 0 {
+		res.consistency = defaults.consistency
+	}
+	if res.serialConsistency == 0 {
+		res.serialConsistency = defaults.serialConsistency
+	}
+	if res.retryPolicy == nil {
+		res.retryPolicy = defaults.retryPolicy
+	}
+	return &res
 }
 
-func (p *params) toQueryParamsProto() *pb.QueryParameters {
+func (p *params) toQueryParamsProto() (*pb.QueryParameters, error) {
 	if p == nil {
-		return nil
+		return nil, nil
 	}
 	res := &pb.QueryParameters{}
 	if p.keyspace != "" {
 		res.Keyspace = &wrapperspb.StringValue{Value: p.keyspace}
 	}
-	return res
+	if p.pageSize > 0 {
+		res.PageSize = &wrapperspb.Int32Value{Value: p.pageSize}
+	}
+	res.Tracing = p.tracing
+	if p.timestamp != nil {
+		res.Timestamp = &wrapperspb.Int64Value{Value: *p.timestamp}
+	}
+	var err error
+	if res.Consistency, err = p.consistency.toProto(); err != nil {
+		return nil, err
+	}
+	if res.SerialConsistency, err = p.serialConsistencyProto(); err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
-func (p *params) toBatchParamsProto() *pb.BatchParameters {
+func (p *params) toBatchParamsProto() (*pb.BatchParameters, error) {
 	if p == nil {
-		return nil
+		return nil, nil
 	}
 	res := &pb.BatchParameters{}
 	if p.keyspace != "" {
 		res.Keyspace = &wrapperspb.StringValue{Value: p.keyspace}
 	}
-	return res
+	res.Tracing = p.tracing
+	if p.timestamp != nil {
+		res.Timestamp = &wrapperspb.Int64Value{Value: *p.timestamp}
+	}
+	var err error
+	if res.Consistency, err = p.consistency.toProto(); err != nil {
+		return nil, err
+	}
+	if res.SerialConsistency, err = p.serialConsistencyProto(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *params) serialConsistencyProto() (*pb.ConsistencyValue, error) {
+	if p.serialConsistency != 0 && !p.serialConsistency.isSerial() {
+		return nil, fmt.Errorf("invalid serial consistency: %v is not SERIAL or LOCAL_SERIAL", p.serialConsistency)
+	}
+	return p.serialConsistency.toProto()
 }
 
 type queryParams struct {
@@ -58,13 +136,54 @@ func (p *queryParams) keyspace(value string) {
 	p.params.keyspace = value
 }
 
+func (p *queryParams) pageSize(value int32) {
+	p.createIfEmpty()
+	p.params.pageSize = value
+}
+
+func (p *queryParams) consistency(value Consistency) {
+	p.createIfEmpty()
+	p.params.consistency = value
+}
+
+func (p *queryParams) serialConsistency(value Consistency) {
+	p.createIfEmpty()
+	p.params.serialConsistency = value
+}
+
+func (p *queryParams) retryPolicy(value RetryPolicy) {
+	p.createIfEmpty()
+	p.params.retryPolicy = value
+}
+
+func (p *queryParams) tracing(value bool) {
+	p.createIfEmpty()
+	p.params.tracing = value
+}
+
+func (p *queryParams) timestamp(value int64) {
+	p.createIfEmpty()
+	p.params.timestamp = &value
+}
+
+func (p *queryParams) idempotent(value bool) {
+	p.createIfEmpty()
+	p.params.idempotent = value
+}
+
 // Query is a configurable and executable Stargate query. Use Client.Query to
 // create a Query.
 type Query struct {
-	client *Client
-	cql    string
-	values []any
+	client      *Client
+	cql         string
+	values      []any
+	named       map[string]any
+	structNamed map[string]any
+	pagingState []byte
 	queryParams
+
+	// err is an error deferred until the query is executed.
+	err error
 }
 
 // Keyspace sets the keyspace to use for the query.
@@ -73,31 +192,249 @@ func (q *Query) Keyspace(value string) *Query {
 	return q
 }
 
+// Consistency sets the consistency level to use for the query.
+func (q *Query) Consistency(value Consistency) *Query {
+	q.queryParams.consistency(value)
+	return q
+}
+
+// SerialConsistency sets the serial consistency level to use for the
+// conditional update phase of lightweight transactions. Must be one of
+// ConsistencySerial or ConsistencyLocalSerial.
+func (q *Query) SerialConsistency(value Consistency) *Query {
+	q.queryParams.serialConsistency(value)
+	return q
+}
+
+// Idempotent marks whether the query can safely be applied more than once.
+// Only idempotent queries are retried by the retry policy.
+func (q *Query) Idempotent(value bool) *Query {
+	q.queryParams.idempotent(value)
+	return q
+}
+
+// RetryPolicy sets the retry policy to use for the query, overriding the
+// client default set by WithRetryPolicy.
+func (q *Query) RetryPolicy(policy RetryPolicy) *Query {
+	q.queryParams.retryPolicy(policy)
+	return q
+}
+
+// Trace enables server-side tracing of the query. Use Iter.Trace or
+// Result.Trace to retrieve the recorded trace.
+func (q *Query) Trace(value bool) *Query {
+	q.queryParams.tracing(value)
+	return q
+}
+
+// WithTimestamp sets the write timestamp of the query, overriding the client
+// timestamp generator and the server time. Cassandra resolves conflicting
+// writes to a cell by keeping the one with the latest timestamp.
+func (q *Query) WithTimestamp(t time.Time) *Query {
+	return q.WithTimestampMicros(t.UnixMicro())
+}
+
+// WithTimestampMicros is like WithTimestamp, but takes the timestamp in
+// microseconds since the Unix epoch, e.g. as read from WRITETIME.
+func (q *Query) WithTimestampMicros(micros int64) *Query {
+	q.queryParams.timestamp(micros)
+	return q
+}
+
+// Bind sets the values of the named bind markers in the query CQL, e.g.
+// ":name", keyed by name. Named markers are rewritten into positional ones
+// before the query is executed. Calling Bind more than once merges the values.
+//
+// Executing the query returns an error if a named marker has no value, if a
+// value is not used by any marker, or if positional values were also passed
+// to Client.Query.
+//
+//	c.Query("UPDATE users SET name = :name WHERE id = :id").
+//	    Bind(map[string]any{"id": id, "name": name})
+func (q *Query) Bind(values map[string]any) *Query {
+	if q.named == nil {
+		q.named = make(map[string]any, len(values))
+	}
+	for k, v := range values {
+		q.named[k] = v
+	}
+	return q
+}
+
+// BindStruct sets the values of the named bind markers in the query CQL from
+// the fields of the struct v, or pointed to by v. Markers are matched to
+// fields by the field's `cql` tag or, if untagged, the snake case form of the
+// field name. Fields tagged with the "omitempty" option are bound as Unset
+// when they hold their zero value. Unlike with Bind, fields not used by any
+// marker are ignored, and values passed to Bind take precedence. Positional
+// bind markers ("?") are not bound, and executing a query with no named
+// markers returns an error.
+//
+// The field values are read when BindStruct is called.
+//
+//	c.Query("UPDATE users SET name = :name, email = :email WHERE id = :id").BindStruct(&u)
+func (q *Query) BindStruct(v any) *Query {
+	cols, err := structColumns(v)
+	if err != nil {
+		q.err = fmt.Errorf("failed to bind struct: %w", err)
+		return q
+	}
+	if q.structNamed == nil {
+		q.structNamed = make(map[string]any, len(cols))
+	}
+	for _, col := range cols {
+		q.structNamed[col.name] = col.value
+	}
+	return q
+}
+
+// PageSize sets the maximum number of rows to fetch per page of results. Exec
+// and Iter fetch subsequent pages as needed. A value of 0 uses the server
+// default.
+func (q *Query) PageSize(value int) *Query {
+	q.queryParams.pageSize(int32(value))
+	return q
+}
+
+// PagingState sets the opaque paging state from which to resume the query, as
+// previously returned by Iter.PageState or Result.PagingState.
+func (q *Query) PagingState(state []byte) *Query {
+	q.pagingState = state
+	return q
+}
+
 // Exec executes the Query using the client that created it and returns the
 // resultant rows.
 func (q *Query) Exec() (Rows, error) {
-	return q.client.execQuery(q)
+	return q.ExecContext(context.Background())
+}
+
+// ExecContext is like Exec, but uses the provided context for the request. The
+// client query timeout still applies if it is shorter than the context
+// deadline.
+func (q *Query) ExecContext(ctx context.Context) (Rows, error) {
+	return q.client.execQuery(ctx, q)
+}
+
+// ExecResult executes a single page of the Query using the client that
+// created it, starting from the paging state set with PagingState, and returns
+// the Result including server warnings, schema change details and the paging
+// state of the following page.
+func (q *Query) ExecResult() (*Result, error) {
+	return q.ExecResultContext(context.Background())
+}
+
+// ExecResultContext is like ExecResult, but uses the provided context for the
+// request.
+func (q *Query) ExecResultContext(ctx context.Context) (*Result, error) {
+	return q.client.execQueryPage(ctx, q, q.pagingState)
+}
+
+// ExecCAS executes a conditional statement, e.g. INSERT ... IF NOT EXISTS or
+// UPDATE ... IF, using the client that created it, and reports whether it was
+// applied. If it was not, the existing values of the columns in the condition
+// returned by the server are copied into dest, in the order they are
+// returned. The serial consistency level of the conditional phase can be set
+// with SerialConsistency.
+//
+//	var version int
+//	applied, err := c.Query(`UPDATE docs SET body = ?, version = ? WHERE id = ? IF version = ?`,
+//	    body, v+1, id, v).ExecCAS(&version)
+func (q *Query) ExecCAS(dest ...any) (applied bool, err error) {
+	return q.ExecCASContext(context.Background(), dest...)
+}
+
+// ExecCASContext is like ExecCAS, but uses the provided context for the
+// request.
+func (q *Query) ExecCASContext(ctx context.Context, dest ...any) (applied bool, err error) {
+	res, err := q.client.execQueryPage(ctx, q, nil)
+	if err != nil {
+		return false, err
+	}
+	return scanCAS(res, dest)
+}
+
+// MapExecCAS is like ExecCAS, but stores the existing values returned by the
+// server in dest, keyed by column name. If dest is nil, the values are
+// discarded.
+func (q *Query) MapExecCAS(dest map[string]any) (applied bool, err error) {
+	return q.MapExecCASContext(context.Background(), dest)
+}
+
+// MapExecCASContext is like MapExecCAS, but uses the provided context for the
+// request.
+func (q *Query) MapExecCASContext(ctx context.Context, dest map[string]any) (applied bool, err error) {
+	res, err := q.client.execQueryPage(ctx, q, nil)
+	if err != nil {
+		return false, err
+	}
+	return mapScanCAS(res, dest)
+}
+
+// Iter executes the Query using the client that created it and returns an
+// Iter over the resultant rows, which fetches pages of rows as needed.
+func (q *Query) Iter() *Iter {
+	return q.IterContext(context.Background())
+}
+
+// IterContext is like Iter, but uses the provided context for all page
+// requests. The client query timeout applies to each page request.
+func (q *Query) IterContext(ctx context.Context) *Iter {
+	return &Iter{
+		ctx:   ctx,
+		query: q,
+		next:  q.pagingState,
+	}
+}
+
+// bound returns the CQL and positional values of the query, with any named
+// values bound.
+func (q *Query) bound() (string, []any, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	}
+	if q.named == nil && q.structNamed == nil {
+		return q.cql, q.values, nil
+	}
+	if len(q.values) > 0 {
+		return "", nil, fmt.Errorf("cannot use both positional and named values")
+	}
+	cql, values, err := bindNamed(q.cql, q.named, q.structNamed)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to bind named values: %w", err)
+	}
+	if len(values) == 0 && len(q.structNamed) > 0 {
+		return "", nil, fmt.Errorf("failed to bind struct: query has no named bind markers")
+	}
+	return cql, values, nil
 }
 
 func (q *Query) toQueryProto() (*pb.Query, error) {
-	vs, err := valuesToProto(q.values)
+	cql, values, err := q.bound()
+	if err != nil {
+		return nil, err
+	}
+	vs, err := valuesToProto(values)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert values to proto: %v", err)
 	}
 	return &pb.Query{
-		Cql:        q.cql,
-		Values:     &pb.Values{Values: vs},
-		Parameters: q.queryParams.params.toQueryParamsProto(),
+		Cql:    cql,
+		Values: &pb.Values{Values: vs},
 	}, nil
 }
 
 func (q *Query) toBatchQueryProto() (*pb.BatchQuery, error) {
-	vs, err := valuesToProto(q.values)
+	cql, values, err := q.bound()
+	if err != nil {
+		return nil, err
+	}
+	vs, err := valuesToProto(values)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert values to proto: %v", err)
 	}
 	return &pb.BatchQuery{
-		Cql:    q.cql,
+		Cql:    cql,
 		Values: &pb.Values{Values: vs},
 	}, nil
 }
@@ -123,6 +460,54 @@ func (b *BatchQuery) Keyspace(value string) *BatchQuery {
 	return b
 }
 
+// Consistency sets the consistency level to use for the batch query.
+func (b *BatchQuery) Consistency(value Consistency) *BatchQuery {
+	b.queryParams.consistency(value)
+	return b
+}
+
+// SerialConsistency sets the serial consistency level to use for the
+// conditional update phase of lightweight transactions in the batch query.
+// Must be one of ConsistencySerial or ConsistencyLocalSerial.
+func (b *BatchQuery) SerialConsistency(value Consistency) *BatchQuery {
+	b.queryParams.serialConsistency(value)
+	return b
+}
+
+// Idempotent marks whether the batch query can safely be applied more than
+// once. Only idempotent batch queries are retried by the retry policy.
+func (b *BatchQuery) Idempotent(value bool) *BatchQuery {
+	b.queryParams.idempotent(value)
+	return b
+}
+
+// RetryPolicy sets the retry policy to use for the batch query, overriding
+// the client default set by WithRetryPolicy.
+func (b *BatchQuery) RetryPolicy(policy RetryPolicy) *BatchQuery {
+	b.queryParams.retryPolicy(policy)
+	return b
+}
+
+// WithTimestamp sets the write timestamp of all statements in the batch
+// query, overriding the client timestamp generator and the server time.
+func (b *BatchQuery) WithTimestamp(t time.Time) *BatchQuery {
+	return b.WithTimestampMicros(t.UnixMicro())
+}
+
+// WithTimestampMicros is like WithTimestamp, but takes the timestamp in
+// microseconds since the Unix epoch.
+func (b *BatchQuery) WithTimestampMicros(micros int64) *BatchQuery {
+	b.queryParams.timestamp(micros)
+	return b
+}
+
+// Trace enables server-side tracing of the batch query. Use Result.Trace to
+// retrieve the recorded trace.
+func (b *BatchQuery) Trace(value bool) *BatchQuery {
+	b.queryParams.tracing(value)
+	return b
+}
+
 func (b *BatchQuery) toProto() (*pb.Batch, error) {
 	qs := make([]*pb.BatchQuery, len(b.queries))
 	for i, q := range b.queries {
@@ -146,8 +531,7 @@ func (b *BatchQuery) toProto() (*pb.Batch, error) {
 	}
 
 	res := &pb.Batch{
-		Queries:    qs,
-		Parameters: b.params.toBatchParamsProto(),
+		Queries: qs,
 	}
 	if t != pb.Batch_LOGGED {
 		res.Type = t
@@ -158,5 +542,61 @@ func (b *BatchQuery) toProto() (*pb.Batch, error) {
 
 // Exec executes the BatchQuery using the client that created it.
 func (b *BatchQuery) Exec() error {
-	return b.client.execBatch(b)
+	return b.ExecContext(context.Background())
+}
+
+// ExecContext is like Exec, but uses the provided context for the request. The
+// client query timeout still applies if it is shorter than the context
+// deadline.
+func (b *BatchQuery) ExecContext(ctx context.Context) error {
+	_, err := b.client.execBatch(ctx, b)
+	return err
+}
+
+// ExecResult executes the BatchQuery using the client that created it and
+// returns the Result including server warnings.
+func (b *BatchQuery) ExecResult() (*Result, error) {
+	return b.ExecResultContext(context.Background())
+}
+
+// ExecResultContext is like ExecResult, but uses the provided context for the
+// request.
+func (b *BatchQuery) ExecResultContext(ctx context.Context) (*Result, error) {
+	return b.client.execBatch(ctx, b)
+}
+
+// ExecCAS executes a batch of conditional statements using the client that
+// created it, and reports whether it was applied. All conditions must hold for
+// the batch to be applied. If it was not, the existing values of the first row
+// returned by the server are copied into dest. The serial consistency level of
+// the conditional phase can be set with SerialConsistency.
+func (b *BatchQuery) ExecCAS(dest ...any) (applied bool, err error) {
+	return b.ExecCASContext(context.Background(), dest...)
+}
+
+// ExecCASContext is like ExecCAS, but uses the provided context for the
+// request.
+func (b *BatchQuery) ExecCASContext(ctx context.Context, dest ...any) (applied bool, err error) {
+	res, err := b.client.execBatch(ctx, b)
+	if err != nil {
+		return false, err
+	}
+	return scanCAS(res, dest)
+}
+
+// MapExecCAS is like ExecCAS, but stores the existing values of the first row
+// returned by the server in dest, keyed by column name. If dest is nil, the
+// values are discarded.
+func (b *BatchQuery) MapExecCAS(dest map[string]any) (applied bool, err error) {
+	return b.MapExecCASContext(context.Background(), dest)
+}
+
+// MapExecCASContext is like MapExecCAS, but uses the provided context for the
+// request.
+func (b *BatchQuery) MapExecCASContext(ctx context.Context, dest map[string]any) (applied bool, err error) {
+	res, err := b.client.execBatch(ctx, b)
+	if err != nil {
+		return false, err
+	}
+	return mapScanCAS(res, dest)
 }
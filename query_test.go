@@ -1,6 +1,8 @@
 package astra
 
 import (
+	"context"
+	"errors"
 	"math"
 	"math/big"
 	"net"
@@ -9,6 +11,9 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
+	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestClient_Query_Exec_allTypes(t *testing.T) {
@@ -198,3 +203,249 @@ func TestClient_Query_Exec_emptyCollections(t *testing.T) {
 		t.Fatalf("got[0].Values() unexpected difference (-want +got):\n%s", diff)
 	}
 }
+
+func TestClient_Query_ExecContext_canceled(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+	c, err := createTestClient()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = c.Query(`SELECT * FROM example.users`).ExecContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Query.ExecContext() got error %v, want %v", err, context.Canceled)
+	}
+
+	err = c.Batch(
+		c.Query(`INSERT INTO example.users (id, name, age) VALUES (12345678-1234-5678-1234-56781234567D,'Dave',33)`),
+	).ExecContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("BatchQuery.ExecContext() got error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestQuery_Iter_paging(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+	c, err := createTestClient()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = c.Query(`CREATE TABLE IF NOT EXISTS test.paging (
+		pk int,
+		ck int,
+		PRIMARY KEY (pk, ck)
+	) WITH default_time_to_live = 30`).Exec()
+	if err != nil {
+		t.Fatalf("failed to create paging table: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		_, err = c.Query(`INSERT INTO test.paging (pk, ck) VALUES (1, ?)`, i).Exec()
+		if err != nil {
+			t.Fatalf("failed to insert row %d: %v", i, err)
+		}
+	}
+
+	q := func() *Query {
+		return c.Query(`SELECT ck FROM test.paging WHERE pk = 1`).PageSize(2)
+	}
+
+	rows, err := q().Exec()
+	if err != nil {
+		t.Fatalf("failed to execute query: %v", err)
+	}
+	if len(rows) != 5 {
+		t.Errorf("Query.PageSize(2).Exec() got %d rows, want 5", len(rows))
+	}
+
+	it := q().Iter()
+	var got []int
+	for len(got) < 2 && it.Next() {
+		var ck int
+		if err := it.Scan(&ck); err != nil {
+			t.Fatalf("failed to scan row: %v", err)
+		}
+		got = append(got, ck)
+	}
+	state := it.PageState()
+	if err := it.Close(); err != nil {
+		t.Fatalf("failed to iterate rows: %v", err)
+	}
+	if state == nil {
+		t.Fatalf("Iter.PageState() got nil after first page, want non-nil")
+	}
+
+	it = q().PagingState(state).Iter()
+	for it.Next() {
+		var ck int
+		if err := it.Scan(&ck); err != nil {
+			t.Fatalf("failed to scan row: %v", err)
+		}
+		got = append(got, ck)
+	}
+	if err := it.Close(); err != nil {
+		t.Fatalf("failed to iterate rows: %v", err)
+	}
+
+	if diff := cmp.Diff([]int{0, 1, 2, 3, 4}, got); diff != "" {
+		t.Errorf("resumed iteration unexpected difference (-want +got):\n%s", diff)
+	}
+}
+
+func TestParams_withDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		p        *params
+		defaults *params
+		want     *params
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name:     "defaults only",
+			defaults: &params{keyspace: "ks", pageSize: 10},
+			want:     &params{keyspace: "ks", pageSize: 10},
+		},
+		{
+			name: "params only",
+			p:    &params{keyspace: "ks"},
+			want: &params{keyspace: "ks"},
+		},
+		{
+			name:     "params override defaults",
+			p:        &params{keyspace: "ks", consistency: ConsistencyOne},
+			defaults: &params{keyspace: "default", pageSize: 10, consistency: ConsistencyQuorum},
+			want:     &params{keyspace: "ks", pageSize: 10, consistency: ConsistencyOne},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.p.withDefaults(tt.defaults)
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(params{})); diff != "" {
+				t.Errorf("withDefaults() unexpected difference (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParams_toQueryParamsProto(t *testing.T) {
+	ts := int64(1659312000000000)
+	in := &params{
+		keyspace:          "ks",
+		pageSize:          10,
+		consistency:       ConsistencyLocalQuorum,
+		serialConsistency: ConsistencyLocalSerial,
+		tracing:           true,
+		timestamp:         &ts,
+	}
+
+	got, err := in.toQueryParamsProto()
+	if err != nil {
+		t.Fatalf("toQueryParamsProto() unexpected error: %v", err)
+	}
+
+	want := &pb.QueryParameters{
+		Keyspace:          &wrapperspb.StringValue{Value: "ks"},
+		PageSize:          &wrapperspb.Int32Value{Value: 10},
+		Consistency:       &pb.ConsistencyValue{Value: pb.Consistency_LOCAL_QUORUM},
+		SerialConsistency: &pb.ConsistencyValue{Value: pb.Consistency_LOCAL_SERIAL},
+		Tracing:           true,
+		Timestamp:         &wrapperspb.Int64Value{Value: ts},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("toQueryParamsProto() unexpected difference (-want +got):\n%s", diff)
+	}
+
+	if _, err := (&params{consistency: Consistency(100)}).toQueryParamsProto(); err == nil {
+		t.Errorf("toQueryParamsProto() with unknown consistency got nil error, want error")
+	}
+}
+
+func TestClient_Query_Exec_udt(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+	c, err := createTestClient()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = c.Query(`CREATE TYPE IF NOT EXISTS test.address (street text, zip int)`).Exec()
+	if err != nil {
+		t.Fatalf("failed to create address type: %v", err)
+	}
+	_, err = c.Query(`CREATE TABLE IF NOT EXISTS test.udts (
+		id uuid PRIMARY KEY,
+		home frozen<address>,
+		others list<frozen<address>>
+	) WITH default_time_to_live = 30`).Exec()
+	if err != nil {
+		t.Fatalf("failed to create udts table: %v", err)
+	}
+
+	type address struct {
+		Street string `cql:"street"`
+		Zip    int    `cql:"zip"`
+	}
+
+	id := uuid.MustParse("f066f76d-5e96-4b52-8d8a-0f51387df76b")
+	home := address{Street: "Main St", Zip: 12345}
+	others := []any{UDT{"street": "Side St", "zip": 54321}}
+
+	_, err = c.Query(`INSERT INTO test.udts (id, home, others) VALUES (?, ?, ?)`, id, home, others).Exec()
+	if err != nil {
+		t.Fatalf("failed to insert values into table: %v", err)
+	}
+
+	rows, err := c.Query(`SELECT home, others FROM test.udts WHERE id = ?`, id).Exec()
+	if err != nil {
+		t.Fatalf("failed to select values: %v", err)
+	}
+
+	wantVals := []any{
+		map[string]any{"street": "Main St", "zip": int64(12345)},
+		[]map[string]any{{"street": "Side St", "zip": int64(54321)}},
+	}
+	if diff := cmp.Diff(wantVals, rows[0].Values()); diff != "" {
+		t.Fatalf("rows[0].Values() unexpected difference (-want +got):\n%s", diff)
+	}
+
+	var gotHome address
+	var gotOthers []address
+	if err := rows[0].Scan(&gotHome, &gotOthers); err != nil {
+		t.Fatalf("failed to scan row: %v", err)
+	}
+	if diff := cmp.Diff(home, gotHome); diff != "" {
+		t.Errorf("scanned home unexpected difference (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]address{{Street: "Side St", Zip: 54321}}, gotOthers); diff != "" {
+		t.Errorf("scanned others unexpected difference (-want +got):\n%s", diff)
+	}
+}
+
+func TestClient_Query_Exec_invalidQuery(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+	c, err := createTestClient()
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, err = c.Query(`SELEC * FROM example.users`).Exec()
+	if !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("Query.Exec() got error %v, want %v", err, ErrInvalidQuery)
+	}
+	var qerr *QueryError
+	if !errors.As(err, &qerr) {
+		t.Fatalf("Query.Exec() got error of type %T, want %T", err, qerr)
+	}
+}
@@ -0,0 +1,134 @@
+package astra
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// reloadingCredentials are TLS transport credentials whose configuration can
+// be replaced. Each handshake uses the current configuration, so replacing it
+// affects new connections only, leaving established connections and the
+// queries in flight on them untouched.
+type reloadingCredentials struct {
+	config atomic.Value // *tls.Config
+}
+
+var _ credentials.TransportCredentials = (*reloadingCredentials)(nil)
+
+func newReloadingCredentials(config *tls.Config) *reloadingCredentials {
+	r := &reloadingCredentials{}
+	r.config.Store(config)
+	return r
+}
+
+// update replaces the configuration used for new connections.
+func (r *reloadingCredentials) update(config *tls.Config) {
+	r.config.Store(config)
+}
+
+func (r *reloadingCredentials) current() credentials.TransportCredentials {
+	return credentials.NewTLS(r.config.Load().(*tls.Config))
+}
+
+func (r *reloadingCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return r.current().ClientHandshake(ctx, authority, conn)
+}
+
+func (r *reloadingCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return r.current().ServerHandshake(conn)
+}
+
+func (r *reloadingCredentials) Info() credentials.ProtocolInfo {
+	return r.current().Info()
+}
+
+func (r *reloadingCredentials) Clone() credentials.TransportCredentials {
+	return newReloadingCredentials(r.config.Load().(*tls.Config))
+}
+
+func (r *reloadingCredentials) OverrideServerName(name string) error {
+	config := r.config.Load().(*tls.Config).Clone()
+	config.ServerName = name
+	r.update(config)
+	return nil
+}
+
+// ReloadBundle reloads the secure connect bundle the client was created with,
+// e.g. after its certificates were rotated, and uses its credentials for new
+// connections. Established connections, and the queries in flight on them,
+// are unaffected. The reloaded bundle must be for the same database.
+//
+// Use WithBundleWatch to reload the bundle automatically.
+func (c *Client) ReloadBundle() error {
+	if c.bundleCreds == nil {
+		return fmt.Errorf("failed to reload secure connect bundle: client does not use one")
+	}
+	bundle, err := c.loadBundle()
+	if err != nil {
+		return fmt.Errorf("failed to reload secure connect bundle: %w", err)
+	}
+	if bundle.Addr() != c.astraURI {
+		return fmt.Errorf("failed to reload secure connect bundle: bundle is for %s, not %s", bundle.Addr(), c.astraURI)
+	}
+	c.bundleCreds.update(bundle.TLSConfig)
+	c.checkBundleExpiry(bundle)
+	return nil
+}
+
+// checkBundleExpiry logs a warning if the certificates of bundle expire within
+// the bundle expiry warning window, once for each expiry time so that
+// reloading an unchanged bundle does not repeat the warning.
+func (c *Client) checkBundleExpiry(bundle *Bundle) {
+	if time.Until(bundle.NotAfter) >= c.bundleExpiryWarning {
+		return
+	}
+	c.expiryWarnedMu.Lock()
+	defer c.expiryWarnedMu.Unlock()
+	if !bundle.NotAfter.Equal(c.expiryWarned) {
+		c.expiryWarned = bundle.NotAfter
+		log.Printf("WARNING: Secure connect bundle certificates expire at %s. Download a new bundle before then.", bundle.NotAfter.Format(time.RFC3339))
+	}
+}
+
+// watchBundle reloads the secure connect bundle every interval until the
+// client is closed. A bundle read from a file is only reloaded when the file
+// changes.
+func (c *Client) watchBundle(interval time.Duration) {
+	var last os.FileInfo
+	if c.bundlePath != "" {
+		last, _ = os.Stat(c.bundlePath)
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-t.C:
+		}
+
+		if c.bundlePath != "" {
+			fi, err := os.Stat(c.bundlePath)
+			if err != nil {
+				log.Printf("WARNING: Failed to check secure connect bundle for changes: %v", err)
+				continue
+			}
+			if last != nil && fi.ModTime().Equal(last.ModTime()) && fi.Size() == last.Size() {
+				continue
+			}
+			last = fi
+		}
+		if err := c.ReloadBundle(); err != nil {
+			log.Printf("WARNING: %v", err)
+		}
+	}
+}
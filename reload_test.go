@@ -0,0 +1,190 @@
+package astra
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReloadingCredentials(t *testing.T) {
+	// gRPC negotiates HTTP/2 with ALPN.
+	ts := httptest.NewUnstartedServer(nil)
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(ts.Certificate())
+
+	creds := newReloadingCredentials(&tls.Config{RootCAs: x509.NewCertPool(), ServerName: "example.com"})
+	handshake := func() error {
+		conn, err := net.Dial("tcp", ts.Listener.Addr().String())
+		if err != nil {
+			t.Fatalf("failed to dial test server: %v", err)
+		}
+		defer conn.Close()
+		tlsConn, _, err := creds.ClientHandshake(context.Background(), "example.com", conn)
+		if err == nil {
+			tlsConn.Close()
+		}
+		return err
+	}
+
+	if err := handshake(); err == nil {
+		t.Errorf("ClientHandshake() got nil error with unknown server CA, want error")
+	}
+	creds.update(&tls.Config{RootCAs: roots, ServerName: "example.com"})
+	if err := handshake(); err != nil {
+		t.Errorf("ClientHandshake() unexpected error after update: %v", err)
+	}
+}
+
+func TestClient_ReloadBundle(t *testing.T) {
+	const host = "0123-us-east1.db.astra.datastax.com"
+	b := testBundle(t, host, time.Now().Add(24*time.Hour))
+	loaded, err := loadBundleZipFromBytes(b, nil)
+	if err != nil {
+		t.Fatalf("failed to load bundle: %v", err)
+	}
+
+	c := &Client{
+		astraURI:    loaded.Addr(),
+		bundleCreds: newReloadingCredentials(&tls.Config{}),
+		loadBundle:  func() (*Bundle, error) { return loadBundleZipFromBytes(b, nil) },
+	}
+	if err := c.ReloadBundle(); err != nil {
+		t.Fatalf("ReloadBundle() unexpected error: %v", err)
+	}
+	if got := c.bundleCreds.config.Load().(*tls.Config); len(got.Certificates) != 1 {
+		t.Errorf("ReloadBundle() got %d certificates, want 1", len(got.Certificates))
+	}
+
+	other := testBundle(t, "4567-eu-west1.db.astra.datastax.com", time.Now().Add(24*time.Hour))
+	c.loadBundle = func() (*Bundle, error) { return loadBundleZipFromBytes(other, nil) }
+	if err := c.ReloadBundle(); err == nil {
+		t.Errorf("ReloadBundle() got nil error for bundle of another database, want error")
+	}
+
+	if err := (&Client{}).ReloadBundle(); err == nil {
+		t.Errorf("ReloadBundle() got nil error for client without bundle, want error")
+	}
+}
+
+func TestClient_watchBundle(t *testing.T) {
+	const host = "0123-us-east1.db.astra.datastax.com"
+	path := filepath.Join(t.TempDir(), "secure-connect-bundle.zip")
+	if err := os.WriteFile(path, testBundle(t, host, time.Now().Add(24*time.Hour)), 0o600); err != nil {
+		t.Fatalf("failed to write bundle: %v", err)
+	}
+	initial, err := loadBundleZipFromPath(path, nil)
+	if err != nil {
+		t.Fatalf("failed to load bundle: %v", err)
+	}
+
+	c := &Client{
+		astraURI:    initial.Addr(),
+		bundlePath:  path,
+		bundleCreds: newReloadingCredentials(initial.TLSConfig),
+		loadBundle:  func() (*Bundle, error) { return loadBundleZipFromPath(path, nil) },
+		done:        make(chan struct{}),
+	}
+	stopped := make(chan struct{})
+	go func() {
+		c.watchBundle(10 * time.Millisecond)
+		close(stopped)
+	}()
+
+	// Unchanged bundles are not reloaded.
+	time.Sleep(50 * time.Millisecond)
+	if c.bundleCreds.config.Load().(*tls.Config) != initial.TLSConfig {
+		t.Errorf("watchBundle() reloaded unchanged bundle")
+	}
+
+	if err := os.WriteFile(path, testBundle(t, host, time.Now().Add(48*time.Hour)), 0o600); err != nil {
+		t.Fatalf("failed to write bundle: %v", err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatalf("failed to change bundle times: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for c.bundleCreds.config.Load().(*tls.Config) == initial.TLSConfig {
+		if time.Now().After(deadline) {
+			t.Fatalf("watchBundle() did not reload changed bundle")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	close(c.done)
+	<-stopped
+}
+
+func TestClient_checkBundleExpiry(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	c := &Client{bundleExpiryWarning: 48 * time.Hour}
+	soon := &Bundle{NotAfter: time.Now().Add(24 * time.Hour)}
+	c.checkBundleExpiry(soon)
+	c.checkBundleExpiry(&Bundle{NotAfter: soon.NotAfter})
+	if got := strings.Count(logs.String(), "WARNING"); got != 1 {
+		t.Errorf("checkBundleExpiry() of unchanged bundle logged %d warnings, want 1", got)
+	}
+
+	c.checkBundleExpiry(&Bundle{NotAfter: soon.NotAfter.Add(time.Hour)})
+	if got := strings.Count(logs.String(), "WARNING"); got != 2 {
+		t.Errorf("checkBundleExpiry() of new bundle logged %d warnings in total, want 2", got)
+	}
+
+	logs.Reset()
+	c.checkBundleExpiry(&Bundle{NotAfter: time.Now().Add(72 * time.Hour)})
+	if logs.Len() != 0 {
+		t.Errorf("checkBundleExpiry() of bundle expiring after the window logged %q", logs.String())
+	}
+}
+
+func TestNewStaticTokenClient_expiredBundle(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	b := testBundle(t, "0123-us-east1.db.astra.datastax.com", time.Now().Add(-time.Minute))
+	_, err := NewStaticTokenClient("token", WithSecureConnectBundleBytes(b))
+	var berr *BundleError
+	if !errors.As(err, &berr) || len(berr.Problems) != 1 || berr.Problems[0].Check != BundleCheckExpiry {
+		t.Fatalf("NewStaticTokenClient() got error %v, want *BundleError with expiry problem", err)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("NewStaticTokenClient() with expired bundle logged %q, want no warning", logs.String())
+	}
+}
+
+func TestWithBundleWatch_fixed(t *testing.T) {
+	b := testBundle(t, "0123-us-east1.db.astra.datastax.com", time.Now().Add(24*time.Hour))
+	t.Setenv("TEST_ASTRA_SCB", base64.StdEncoding.EncodeToString(b))
+
+	tests := map[string]StaticTokenConnectConfig{
+		"bytes":  WithSecureConnectBundleBytes(b),
+		"reader": WithSecureConnectBundleReader(bytes.NewReader(b), int64(len(b))),
+		"env":    WithSecureConnectBundleEnv("TEST_ASTRA_SCB"),
+	}
+	for name, connection := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewStaticTokenClient("token", connection, WithBundleWatch(time.Minute))
+			if err == nil || !strings.Contains(err.Error(), "cannot watch") {
+				t.Errorf("NewStaticTokenClient() with watched %s bundle got error %v, want error", name, err)
+			}
+		})
+	}
+}
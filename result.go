@@ -0,0 +1,154 @@
+package astra
+
+import (
+	"fmt"
+
+	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
+)
+
+// appliedColumn is the column reporting whether a conditional statement was
+// applied.
+const appliedColumn = "[applied]"
+
+// Result is the result of executing a single request, as returned by
+// Query.ExecResult and BatchQuery.ExecResult.
+type Result struct {
+	// Rows are the rows returned by the request, if any.
+	Rows Rows
+	// Columns are the columns of the result set returned by the request,
+	// which are known even if it has no rows, or nil if the request returned
+	// no result set.
+	Columns []Column
+	// PagingState is the opaque paging state of the following page of
+	// results, or nil if there are no further pages. Pass it to
+	// Query.PagingState to fetch that page.
+	PagingState []byte
+	// Warnings are the warnings returned by the server, e.g. for reading many
+	// tombstones or executing large batches.
+	Warnings []string
+	// SchemaChange describes the schema change made by a DDL statement, or
+	// is nil for other statements.
+	SchemaChange *SchemaChange
+	// Trace is the server-side trace of the request, or nil if tracing was
+	// not enabled.
+	Trace *Trace
+	// Applied reports whether a conditional statement was applied. It is
+	// true for statements without conditions.
+	Applied bool
+}
+
+// TraceID returns the ID of the server-side trace of the request, or the
+// empty string if tracing was not enabled.
+func (r *Result) TraceID() string {
+	if r.Trace == nil {
+		return ""
+	}
+	return r.Trace.ID
+}
+
+func newResult(resp *pb.Response) (*Result, error) {
+	res := &Result{
+		Warnings: resp.GetWarnings(),
+		Trace:    newTrace(resp.GetTraces()),
+		Applied:  true,
+	}
+	switch r := resp.Result.(type) {
+	case *pb.Response_ResultSet:
+		rows, err := newRowsFromResultSet(r.ResultSet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create rows from result set: %v", err)
+		}
+		res.Rows = rows
+		res.Columns = newColumns(r.ResultSet.GetColumns())
+		res.PagingState = r.ResultSet.GetPagingState().GetValue()
+		if len(rows) > 0 {
+			if v, ok := rows[0].Get(appliedColumn); ok {
+				applied, _ := v.(bool)
+				res.Applied = applied
+			}
+		}
+	case *pb.Response_SchemaChange:
+		res.SchemaChange = newSchemaChange(r.SchemaChange)
+	case nil:
+	default:
+		return nil, fmt.Errorf("unexpected response type: %T, %v", resp.Result, resp.Result)
+	}
+	return res, nil
+}
+
+// SchemaChangeType is the kind of a schema change.
+type SchemaChangeType uint8
+
+// Schema change types.
+const (
+	SchemaCreated SchemaChangeType = iota
+	SchemaUpdated
+	SchemaDropped
+)
+
+var schemaChangeTypeNames = map[SchemaChangeType]string{
+	SchemaCreated: "CREATED",
+	SchemaUpdated: "UPDATED",
+	SchemaDropped: "DROPPED",
+}
+
+func (t SchemaChangeType) String() string {
+	if n, ok := schemaChangeTypeNames[t]; ok {
+		return n
+	}
+	return fmt.Sprintf("SchemaChangeType(%d)", uint8(t))
+}
+
+// SchemaChangeTarget is the kind of schema element affected by a schema
+// change.
+type SchemaChangeTarget uint8
+
+// Schema change targets.
+const (
+	TargetKeyspace SchemaChangeTarget = iota
+	TargetTable
+	TargetType
+	TargetFunction
+	TargetAggregate
+)
+
+var schemaChangeTargetNames = map[SchemaChangeTarget]string{
+	TargetKeyspace:  "KEYSPACE",
+	TargetTable:     "TABLE",
+	TargetType:      "TYPE",
+	TargetFunction:  "FUNCTION",
+	TargetAggregate: "AGGREGATE",
+}
+
+func (t SchemaChangeTarget) String() string {
+	if n, ok := schemaChangeTargetNames[t]; ok {
+		return n
+	}
+	return fmt.Sprintf("SchemaChangeTarget(%d)", uint8(t))
+}
+
+// SchemaChange describes a schema change made by a DDL statement.
+type SchemaChange struct {
+	// Type is the kind of change.
+	Type SchemaChangeType
+	// Target is the kind of schema element changed.
+	Target SchemaChangeTarget
+	// Keyspace is the keyspace changed, or containing the element changed.
+	Keyspace string
+	// Name is the name of the element changed, or empty if the target is a
+	// keyspace.
+	Name string
+	// ArgumentTypes are the CQL argument types of the function or aggregate
+	// changed.
+	ArgumentTypes []string
+}
+
+func newSchemaChange(sc *pb.SchemaChange) *SchemaChange {
+	return &SchemaChange{
+		Type:          SchemaChangeType(sc.GetChangeType()),
+		Target:        SchemaChangeTarget(sc.GetTarget()),
+		Keyspace:      sc.GetKeyspace(),
+		Name:          sc.GetName().GetValue(),
+		ArgumentTypes: sc.GetArgumentTypes(),
+	}
+}
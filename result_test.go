@@ -0,0 +1,108 @@
+package astra
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestNewResult(t *testing.T) {
+	boolType := &pb.TypeSpec{Spec: &pb.TypeSpec_Basic_{Basic: pb.TypeSpec_BOOLEAN}}
+
+	tests := []struct {
+		name string
+		resp *pb.Response
+		want *Result
+	}{
+		{
+			name: "empty",
+			resp: &pb.Response{},
+			want: &Result{Applied: true},
+		},
+		{
+			name: "warnings and trace",
+			resp: &pb.Response{
+				Warnings: []string{"Read 1001 live rows and 5000 tombstone cells"},
+				Traces:   &pb.Traces{Id: "trace-id"},
+				Result: &pb.Response_ResultSet{ResultSet: &pb.ResultSet{
+					PagingState: &wrapperspb.BytesValue{Value: []byte("next")},
+				}},
+			},
+			want: &Result{
+				PagingState: []byte("next"),
+				Warnings:    []string{"Read 1001 live rows and 5000 tombstone cells"},
+				Trace:       &Trace{ID: "trace-id", Events: []TraceEvent{}},
+				Applied:     true,
+			},
+		},
+		{
+			name: "schema change",
+			resp: &pb.Response{
+				Result: &pb.Response_SchemaChange{SchemaChange: &pb.SchemaChange{
+					ChangeType: pb.SchemaChange_DROPPED,
+					Target:     pb.SchemaChange_TABLE,
+					Keyspace:   "ks",
+					Name:       &wrapperspb.StringValue{Value: "users"},
+				}},
+			},
+			want: &Result{
+				SchemaChange: &SchemaChange{
+					Type:     SchemaDropped,
+					Target:   TargetTable,
+					Keyspace: "ks",
+					Name:     "users",
+				},
+				Applied: true,
+			},
+		},
+		{
+			name: "not applied",
+			resp: &pb.Response{
+				Result: &pb.Response_ResultSet{ResultSet: &pb.ResultSet{
+					Columns: []*pb.ColumnSpec{{Name: "[applied]", Type: boolType}},
+					Rows:    []*pb.Row{{Values: []*pb.Value{{Inner: &pb.Value_Boolean{Boolean: false}}}}},
+				}},
+			},
+			want: &Result{
+				Columns: []Column{{Name: "[applied]", Type: &CQLType{Kind: TypeBoolean}}},
+				Applied: false,
+			},
+		},
+		{
+			name: "no rows",
+			resp: &pb.Response{
+				Result: &pb.Response_ResultSet{ResultSet: &pb.ResultSet{
+					Columns: []*pb.ColumnSpec{{Name: "[applied]", Type: boolType}},
+				}},
+			},
+			want: &Result{
+				Columns: []Column{{Name: "[applied]", Type: &CQLType{Kind: TypeBoolean}}},
+				Applied: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newResult(tt.resp)
+			if err != nil {
+				t.Fatalf("newResult() unexpected error: %v", err)
+			}
+			// Conversion of rows is covered by TestNewRowsFromResultSet.
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(Result{}, "Rows")); diff != "" {
+				t.Errorf("newResult() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSchemaChange_String(t *testing.T) {
+	if got, want := SchemaUpdated.String(), "UPDATED"; got != want {
+		t.Errorf("SchemaChangeType.String() got %q, want %q", got, want)
+	}
+	if got, want := TargetAggregate.String(), "AGGREGATE"; got != want {
+		t.Errorf("SchemaChangeTarget.String() got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,156 @@
+package astra
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// RetryPolicy decides whether a failed query should be retried. Use
+// WithRetryPolicy to set the default policy for a client, or
+// Query.RetryPolicy and BatchQuery.RetryPolicy to override it. Retry policies
+// are only consulted for queries marked idempotent.
+type RetryPolicy interface {
+	// Retry is called after each failed attempt and returns whether, when
+	// and at which consistency level to attempt the query again.
+	Retry(info RetryInfo) RetryDecision
+}
+
+// RetryInfo describes a failed query attempt.
+type RetryInfo struct {
+	// Attempts is the number of attempts made so far, including the failed
+	// one.
+	Attempts int
+	// Err is the error returned by the failed attempt. It is always a
+	// *QueryError.
+	Err error
+	// Consistency is the consistency level of the failed attempt, or 0 if
+	// the server default was used.
+	Consistency Consistency
+}
+
+// RetryDecision is the decision made by a RetryPolicy.
+type RetryDecision struct {
+	// Retry reports whether to attempt the query again.
+	Retry bool
+	// Delay is the time to wait before the next attempt.
+	Delay time.Duration
+	// Consistency is the consistency level to use for the next attempt. If
+	// 0, the consistency level of the failed attempt is kept.
+	Consistency Consistency
+}
+
+const (
+	defaultRetryMinDelay = 100 * time.Millisecond
+	defaultRetryMaxDelay = 10 * time.Second
+)
+
+// ExponentialBackoffRetryPolicy retries queries which failed due to transient
+// server conditions, i.e. unavailable replicas, timeouts and overload, with
+// an exponentially increasing, jittered delay between attempts.
+type ExponentialBackoffRetryPolicy struct {
+	// MaxRetries is the maximum number of retries after the first attempt.
+	MaxRetries int
+	// MinDelay is the base delay before the first retry. Defaults to 100ms.
+	MinDelay time.Duration
+	// MaxDelay caps the delay between attempts. Defaults to 10s.
+	MaxDelay time.Duration
+}
+
+// Retry implements RetryPolicy.
+func (p *ExponentialBackoffRetryPolicy) Retry(info RetryInfo) RetryDecision {
+	if info.Attempts > p.MaxRetries || !isTransient(info.Err) {
+		return RetryDecision{}
+	}
+	return RetryDecision{Retry: true, Delay: p.delay(info.Attempts)}
+}
+
+// delay returns the delay before the given retry, using "equal jitter": half
+// of the exponential backoff is fixed and half is random.
+func (p *ExponentialBackoffRetryPolicy) delay(retry int) time.Duration {
+	minDelay, maxDelay := p.MinDelay, p.MaxDelay
+	if minDelay <= 0 {
+		minDelay = defaultRetryMinDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+	d := minDelay
+	for i := 1; i < retry && d < maxDelay; i++ {
+		d *= 2
+	}
+	if d > maxDelay {
+		d = maxDelay
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// isTransient reports whether err was caused by a server condition which may
+// not persist.
+func isTransient(err error) bool {
+	var qerr *QueryError
+	if errors.As(err, &qerr) && qerr.Code == codes.Unavailable {
+		return true
+	}
+	return errors.Is(err, ErrUnavailable) ||
+		errors.Is(err, ErrReadTimeout) ||
+		errors.Is(err, ErrWriteTimeout) ||
+		errors.Is(err, ErrOverloaded)
+}
+
+// DowngradingConsistencyRetryPolicy retries a query at most once, at a lower
+// consistency level if not enough replicas were available or responded to
+// satisfy the original one.
+//
+// This policy may break consistency guarantees: a read at the downgraded
+// level may not observe a previously acknowledged write. Use it only where
+// availability is preferred over consistency.
+type DowngradingConsistencyRetryPolicy struct{}
+
+// Retry implements RetryPolicy.
+func (DowngradingConsistencyRetryPolicy) Retry(info RetryInfo) RetryDecision {
+	var qerr *QueryError
+	if info.Attempts > 1 || !errors.As(info.Err, &qerr) {
+		return RetryDecision{}
+	}
+	switch {
+	case errors.Is(qerr, ErrUnavailable):
+		return downgrade(qerr.Received)
+	case errors.Is(qerr, ErrReadTimeout):
+		if qerr.Received < qerr.Required {
+			return downgrade(qerr.Received)
+		}
+		// Enough replicas responded but the data was not retrieved, so the
+		// read is likely to succeed at the same level.
+		if !qerr.DataPresent {
+			return RetryDecision{Retry: true}
+		}
+	case errors.Is(qerr, ErrWriteTimeout):
+		switch qerr.WriteType {
+		case "BATCH_LOG":
+			// The batch log write timed out, the batch was not applied.
+			return RetryDecision{Retry: true}
+		case "UNLOGGED_BATCH":
+			return downgrade(qerr.Received)
+		}
+	}
+	return RetryDecision{}
+}
+
+// downgrade returns a decision to retry at the highest consistency level
+// which can be satisfied by the given number of replicas.
+func downgrade(replicas int) RetryDecision {
+	switch {
+	case replicas >= 3:
+		return RetryDecision{Retry: true, Consistency: ConsistencyThree}
+	case replicas == 2:
+		return RetryDecision{Retry: true, Consistency: ConsistencyTwo}
+	case replicas == 1:
+		return RetryDecision{Retry: true, Consistency: ConsistencyOne}
+	default:
+		return RetryDecision{}
+	}
+}
@@ -0,0 +1,229 @@
+package astra
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeStargateClient is a pb.StargateClient which returns the given errors in
+// order, then succeeds with the response returned by handler, if set, or resp.
+type fakeStargateClient struct {
+	errs    []error
+	resp    *pb.Response
+	handler func(cql string) *pb.Response
+
+	queries       []string
+	consistencies []pb.Consistency
+	tokens        []string
+}
+
+func (f *fakeStargateClient) respond(ctx context.Context, cql string, p interface{ GetConsistency() *pb.ConsistencyValue }) (*pb.Response, error) {
+	f.queries = append(f.queries, cql)
+	f.consistencies = append(f.consistencies, p.GetConsistency().GetValue())
+	md, _ := metadata.FromOutgoingContext(ctx)
+	f.tokens = append(f.tokens, strings.Join(md.Get(tokenHeader), ","))
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return nil, err
+	}
+	if f.handler != nil {
+		return f.handler(cql), nil
+	}
+	if f.resp != nil {
+		return f.resp, nil
+	}
+	return &pb.Response{}, nil
+}
+
+func (f *fakeStargateClient) ExecuteQuery(ctx context.Context, in *pb.Query, _ ...grpc.CallOption) (*pb.Response, error) {
+	return f.respond(ctx, in.GetCql(), in.GetParameters())
+}
+
+func (f *fakeStargateClient) ExecuteBatch(ctx context.Context, in *pb.Batch, _ ...grpc.CallOption) (*pb.Response, error) {
+	return f.respond(ctx, "", in.GetParameters())
+}
+
+func TestClient_retry(t *testing.T) {
+	st, err := status.New(codes.Unavailable, "unavailable").
+		WithDetails(&pb.Unavailable{Consistency: pb.Consistency_QUORUM, Required: 3, Alive: 2})
+	if err != nil {
+		t.Fatalf("failed to create status: %v", err)
+	}
+	unavailable := st.Err()
+	invalid := status.Error(codes.InvalidArgument, "invalid")
+	backoff := &ExponentialBackoffRetryPolicy{MaxRetries: 2, MinDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	tests := []struct {
+		name              string
+		errs              []error
+		configure         func(q *Query)
+		wantErr           error
+		wantAttempts      int
+		wantConsistencies []pb.Consistency
+	}{
+		{
+			name: "not idempotent",
+			errs: []error{unavailable},
+			configure: func(q *Query) {
+				q.RetryPolicy(backoff)
+			},
+			wantErr:           ErrUnavailable,
+			wantAttempts:      1,
+			wantConsistencies: []pb.Consistency{pb.Consistency_QUORUM},
+		},
+		{
+			name: "no policy",
+			errs: []error{unavailable},
+			configure: func(q *Query) {
+				q.Idempotent(true)
+			},
+			wantErr:           ErrUnavailable,
+			wantAttempts:      1,
+			wantConsistencies: []pb.Consistency{pb.Consistency_QUORUM},
+		},
+		{
+			name: "backoff succeeds",
+			errs: []error{unavailable, unavailable},
+			configure: func(q *Query) {
+				q.Idempotent(true).RetryPolicy(backoff)
+			},
+			wantConsistencies: []pb.Consistency{pb.Consistency_QUORUM, pb.Consistency_QUORUM, pb.Consistency_QUORUM},
+		},
+		{
+			name: "backoff exhausted",
+			errs: []error{unavailable, unavailable, unavailable},
+			configure: func(q *Query) {
+				q.Idempotent(true).RetryPolicy(backoff)
+			},
+			wantErr:           ErrUnavailable,
+			wantAttempts:      3,
+			wantConsistencies: []pb.Consistency{pb.Consistency_QUORUM, pb.Consistency_QUORUM, pb.Consistency_QUORUM},
+		},
+		{
+			name: "backoff not transient",
+			errs: []error{invalid},
+			configure: func(q *Query) {
+				q.Idempotent(true).RetryPolicy(backoff)
+			},
+			wantErr:           ErrInvalidQuery,
+			wantAttempts:      1,
+			wantConsistencies: []pb.Consistency{pb.Consistency_QUORUM},
+		},
+		{
+			name: "downgrade",
+			errs: []error{unavailable},
+			configure: func(q *Query) {
+				q.Idempotent(true).RetryPolicy(DowngradingConsistencyRetryPolicy{})
+			},
+			wantConsistencies: []pb.Consistency{pb.Consistency_QUORUM, pb.Consistency_TWO},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeStargateClient{errs: tt.errs}
+			c := &Client{sgClient: f, timeout: defaultTimeout}
+			q := c.Query(`SELECT * FROM users`).Consistency(ConsistencyQuorum)
+			tt.configure(q)
+
+			_, err := q.Exec()
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Exec() unexpected error: %v", err)
+			}
+			if tt.wantErr != nil {
+				var qerr *QueryError
+				if !errors.Is(err, tt.wantErr) || !errors.As(err, &qerr) {
+					t.Fatalf("Exec() got error %v, want %v", err, tt.wantErr)
+				}
+				if qerr.Attempts != tt.wantAttempts {
+					t.Errorf("QueryError.Attempts got %d, want %d", qerr.Attempts, tt.wantAttempts)
+				}
+			}
+			if diff := cmp.Diff(tt.wantConsistencies, f.consistencies); diff != "" {
+				t.Errorf("consistency levels mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestClient_retry_defaultPolicy(t *testing.T) {
+	f := &fakeStargateClient{errs: []error{status.Error(codes.Unavailable, "unavailable")}}
+	c := &Client{sgClient: f, timeout: defaultTimeout}
+	WithRetryPolicy(&ExponentialBackoffRetryPolicy{MaxRetries: 1, MinDelay: time.Millisecond})(c)
+
+	if err := c.Batch(c.Query(`DELETE FROM users`)).Idempotent(true).Exec(); err != nil {
+		t.Fatalf("BatchQuery.Exec() unexpected error: %v", err)
+	}
+	if len(f.consistencies) != 2 {
+		t.Errorf("got %d attempts, want 2", len(f.consistencies))
+	}
+}
+
+func TestDowngradingConsistencyRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *QueryError
+		want RetryDecision
+	}{
+		{
+			name: "read timeout not enough replicas",
+			err:  &QueryError{kind: ErrReadTimeout, Required: 3, Received: 1},
+			want: RetryDecision{Retry: true, Consistency: ConsistencyOne},
+		},
+		{
+			name: "read timeout data missing",
+			err:  &QueryError{kind: ErrReadTimeout, Required: 2, Received: 2},
+			want: RetryDecision{Retry: true},
+		},
+		{
+			name: "read timeout data present",
+			err:  &QueryError{kind: ErrReadTimeout, Required: 2, Received: 2, DataPresent: true},
+			want: RetryDecision{},
+		},
+		{
+			name: "write timeout batch log",
+			err:  &QueryError{kind: ErrWriteTimeout, WriteType: "BATCH_LOG"},
+			want: RetryDecision{Retry: true},
+		},
+		{
+			name: "write timeout simple",
+			err:  &QueryError{kind: ErrWriteTimeout, WriteType: "SIMPLE", Received: 1},
+			want: RetryDecision{},
+		},
+		{
+			name: "unavailable no replicas",
+			err:  &QueryError{kind: ErrUnavailable},
+			want: RetryDecision{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DowngradingConsistencyRetryPolicy{}.Retry(RetryInfo{Attempts: 1, Err: tt.err})
+			if got != tt.want {
+				t.Errorf("Retry() got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExponentialBackoffRetryPolicy_delay(t *testing.T) {
+	p := &ExponentialBackoffRetryPolicy{MinDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+	for retry, max := range map[int]time.Duration{1: 10, 2: 20, 3: 40, 4: 50, 10: 50} {
+		max *= time.Millisecond
+		for i := 0; i < 20; i++ {
+			if d := p.delay(retry); d < max/2 || d > max {
+				t.Errorf("delay(%d) got %v, want in [%v, %v]", retry, d, max/2, max)
+			}
+		}
+	}
+}
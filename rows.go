@@ -2,13 +2,15 @@ package astra
 
 import (
 	"fmt"
+	"reflect"
 
 	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
 )
 
 type colSpec struct {
-	names []string
-	idxs  map[string]int
+	names   []string
+	idxs    map[string]int
+	columns []Column
 }
 
 // Row represents a row of data from an Astra table.
@@ -30,6 +32,75 @@ func (r *Row) Scan(dest ...any) error {
 	return nil
 }
 
+// ScanStruct copies the values from the row into the fields of the struct
+// pointed to by dest. Columns are matched to fields by the field's `cql` tag
+// or, if untagged, the snake case form of the field name, e.g. a field named
+// UserID matches a column named "user_id". Fields of untagged embedded structs
+// are matched as if they were fields of dest. Fields tagged `cql:"-"` are
+// ignored.
+//
+// NULL columns are assigned the zero value of their field, as are NULL UDT
+// fields. It returns an error if a column has no matching field, or if a
+// column value cannot be assigned to its field. Fields with no matching column
+// are left unchanged.
+func (r *Row) ScanStruct(dest any) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("destination must be a non-nil pointer to a struct, got %T", dest)
+	}
+	if r.spec == nil {
+		return fmt.Errorf("row has no column metadata")
+	}
+	sv := dv.Elem()
+	fs := cqlFieldMap(sv.Type())
+	for i, name := range r.spec.names {
+		f, ok := fs[name]
+		if !ok {
+			return fmt.Errorf("missing destination field for column %q in %T", name, dest)
+		}
+		fv := fieldByIndexAlloc(sv, f.index)
+		if r.values[i] == nil {
+			fv.Set(reflect.Zero(fv.Type()))
+			continue
+		}
+		if err := convertAssign(fv.Addr().Interface(), r.values[i]); err != nil {
+			return fmt.Errorf("failed to assign column %q to field of type %s: %w", name, fv.Type(), err)
+		}
+	}
+	return nil
+}
+
+// Columns returns a copy of the columns of the row, or nil if the row has no
+// column metadata.
+func (r *Row) Columns() []Column {
+	if r.spec == nil {
+		return nil
+	}
+	return append([]Column(nil), r.spec.columns...)
+}
+
+// Index returns the index of the named column in the row, or -1 if there is no
+// such column.
+func (r *Row) Index(name string) int {
+	if r.spec == nil {
+		return -1
+	}
+	if i, ok := r.spec.idxs[name]; ok {
+		return i
+	}
+	return -1
+}
+
+// Get returns the value of the named column in the row, and whether the column
+// exists.
+func (r *Row) Get(name string) (any, bool) {
+	i := r.Index(name)
+	if i < 0 {
+		return nil, false
+	}
+	return r.values[i], true
+}
+
 // Values returns the values in the row.
 func (r *Row) Values() []any {
 	return r.values
@@ -43,12 +114,42 @@ func (r *Row) String() string {
 // Rows represents a list of Astra table rows.
 type Rows []Row
 
+// Columns returns the columns of the rows, or nil if there are no rows. Use
+// Query.ExecResult to obtain the columns of a result regardless, as
+// Result.Columns, e.g. to render the headers of an empty result.
+func (rs Rows) Columns() []Column {
+	if len(rs) == 0 {
+		return nil
+	}
+	return rs[0].Columns()
+}
+
+// ScanAll copies the values from each row into a new struct of type T, as
+// described by Row.ScanStruct, and returns the structs in row order.
+//
+//	type User struct {
+//	    ID   uuid.UUID
+//	    Name string `cql:"username"`
+//	}
+//
+//	users, err := astra.ScanAll[User](rows)
+func ScanAll[T any](rows Rows) ([]T, error) {
+	res := make([]T, len(rows))
+	for i := range rows {
+		if err := rows[i].ScanStruct(&res[i]); err != nil {
+			return nil, fmt.Errorf("failed to scan row at index %d: %w", i, err)
+		}
+	}
+	return res, nil
+}
+
 func newRowsFromResultSet(rs *pb.ResultSet) (Rows, error) {
 	var cs *colSpec
 	if cols := rs.Columns; cols != nil {
 		cs = &colSpec{
-			names: make([]string, len(cols)),
-			idxs:  make(map[string]int, len(cols)),
+			names:   make([]string, len(cols)),
+			idxs:    make(map[string]int, len(cols)),
+			columns: newColumns(cols),
 		}
 		for i, col := range cols {
 			cs.names[i] = col.Name
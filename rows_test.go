@@ -37,6 +37,10 @@ func TestNewRowsFromResultSet(t *testing.T) {
 			spec: &colSpec{
 				names: []string{"name", "age"},
 				idxs:  map[string]int{"name": 0, "age": 1},
+				columns: []Column{
+					{Name: "name", Type: &CQLType{Kind: TypeText}},
+					{Name: "age", Type: &CQLType{Kind: TypeInt}},
+				},
 			},
 			values: []interface{}{"Alice", int64(30)},
 		},
@@ -45,6 +49,13 @@ func TestNewRowsFromResultSet(t *testing.T) {
 	if diff := cmp.Diff(want, got, cmp.AllowUnexported(Row{}, colSpec{})); diff != "" {
 		t.Fatalf("newRowsFromResultSet(%q) unexpected difference (-want +got):\n%s", in, diff)
 	}
+
+	// Columns returns a copy, so changes do not affect other rows.
+	cols := got[0].Columns()
+	cols[0].Name = "changed"
+	if name := got.Columns()[0].Name; name != "name" {
+		t.Errorf("Columns() got name %q after changing a copy, want %q", name, "name")
+	}
 }
 
 func TestRow_Scan(t *testing.T) {
@@ -71,5 +82,158 @@ func TestRow_Scan(t *testing.T) {
 }
 
 func TestScanToStruct(t *testing.T) {
+	type Base struct {
+		ID string `cql:"id"`
+	}
+	type user struct {
+		Base
+		Name    string
+		UserAge int16 `cql:"age"`
+		Note    string
+	}
+
+	in := &Row{
+		spec: &colSpec{
+			names: []string{"id", "name", "age"},
+			idxs:  map[string]int{"id": 0, "name": 1, "age": 2},
+		},
+		values: []interface{}{"1234", "Alice", int64(30)},
+	}
+
+	got := user{Note: "unchanged"}
+	if err := in.ScanStruct(&got); err != nil {
+		t.Fatalf("%q.ScanStruct(%T) failed to scan values: %v", in, &got, err)
+	}
+
+	want := user{Base: Base{ID: "1234"}, Name: "Alice", UserAge: 30, Note: "unchanged"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("%q.ScanStruct(%T) unexpected difference (-want +got):\n%s", in, &got, diff)
+	}
 
+	gotAll, err := ScanAll[user](Rows{*in, *in})
+	if err != nil {
+		t.Fatalf("ScanAll(%q) failed to scan rows: %v", in, err)
+	}
+	want.Note = ""
+	if diff := cmp.Diff([]user{want, want}, gotAll); diff != "" {
+		t.Errorf("ScanAll(%q) unexpected difference (-want +got):\n%s", in, diff)
+	}
+}
+
+func TestScanToStruct_null(t *testing.T) {
+	type scores struct {
+		Name   string
+		Age    int
+		Score  float64
+		Active bool
+		Nick   *string
+	}
+
+	in := &Row{
+		spec: &colSpec{
+			names: []string{"name", "age", "score", "active", "nick"},
+			idxs:  map[string]int{"name": 0, "age": 1, "score": 2, "active": 3, "nick": 4},
+		},
+		values: []interface{}{nil, nil, nil, nil, nil},
+	}
+
+	nick := "al"
+	got := scores{Name: "Alice", Age: 30, Score: 1.5, Active: true, Nick: &nick}
+	if err := in.ScanStruct(&got); err != nil {
+		t.Fatalf("%q.ScanStruct(%T) failed to scan NULL values: %v", in, &got, err)
+	}
+	if diff := cmp.Diff(scores{}, got); diff != "" {
+		t.Errorf("%q.ScanStruct(%T) unexpected difference (-want +got):\n%s", in, &got, diff)
+	}
+}
+
+func TestScanToStruct_errors(t *testing.T) {
+	in := &Row{
+		spec: &colSpec{
+			names: []string{"name", "age"},
+			idxs:  map[string]int{"name": 0, "age": 1},
+		},
+		values: []interface{}{"Alice", "thirty"},
+	}
+
+	tests := []struct {
+		name string
+		dest any
+	}{
+		{name: "not a pointer", dest: struct{ Name string }{}},
+		{name: "not a struct", dest: new(string)},
+		{name: "missing column", dest: &struct{ Name string }{}},
+		{name: "unassignable column", dest: &struct {
+			Name string
+			Age  int
+		}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := in.ScanStruct(tt.dest); err == nil {
+				t.Errorf("%q.ScanStruct(%T) got nil error, want error", in, tt.dest)
+			}
+		})
+	}
+}
+
+func TestRow_Get(t *testing.T) {
+	in := &Row{
+		spec: &colSpec{
+			names: []string{"name", "age"},
+			idxs:  map[string]int{"name": 0, "age": 1},
+		},
+		values: []interface{}{"Alice", int64(30)},
+	}
+
+	if got := in.Index("age"); got != 1 {
+		t.Errorf("%q.Index(%q) got %d, want %d", in, "age", got, 1)
+	}
+	if got := in.Index("missing"); got != -1 {
+		t.Errorf("%q.Index(%q) got %d, want %d", in, "missing", got, -1)
+	}
+	if got, ok := in.Get("name"); !ok || got != "Alice" {
+		t.Errorf("%q.Get(%q) got (%v, %t), want (%v, %t)", in, "name", got, ok, "Alice", true)
+	}
+	if got, ok := in.Get("missing"); ok {
+		t.Errorf("%q.Get(%q) got (%v, %t), want (%v, %t)", in, "missing", got, ok, nil, false)
+	}
+}
+
+func TestNewCQLType(t *testing.T) {
+	text := &pb.TypeSpec{Spec: &pb.TypeSpec_Basic_{Basic: pb.TypeSpec_TEXT}}
+	in := &pb.TypeSpec{Spec: &pb.TypeSpec_Map_{Map: &pb.TypeSpec_Map{
+		Key: text,
+		Value: &pb.TypeSpec{Spec: &pb.TypeSpec_List_{List: &pb.TypeSpec_List{
+			Element: &pb.TypeSpec{Spec: &pb.TypeSpec_Tuple_{Tuple: &pb.TypeSpec_Tuple{Elements: []*pb.TypeSpec{
+				{Spec: &pb.TypeSpec_Basic_{Basic: pb.TypeSpec_INT}},
+				{Spec: &pb.TypeSpec_Udt_{Udt: &pb.TypeSpec_Udt{Fields: map[string]*pb.TypeSpec{
+					"zip":    {Spec: &pb.TypeSpec_Basic_{Basic: pb.TypeSpec_INT}},
+					"street": text,
+				}}}},
+			}}}},
+		}}},
+	}}}
+
+	got := newCQLType(in)
+
+	want := &CQLType{
+		Kind: TypeMap,
+		Key:  &CQLType{Kind: TypeText},
+		Elem: &CQLType{Kind: TypeList, Elem: &CQLType{Kind: TypeTuple, Elems: []*CQLType{
+			{Kind: TypeInt},
+			{Kind: TypeUDT, Fields: map[string]*CQLType{
+				"street": {Kind: TypeText},
+				"zip":    {Kind: TypeInt},
+			}},
+		}}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("newCQLType(%v) unexpected difference (-want +got):\n%s", in, diff)
+	}
+
+	wantStr := "map<text, list<tuple<int, udt<street text, zip int>>>>"
+	if got := got.String(); got != wantStr {
+		t.Errorf("newCQLType(%v).String() got %q, want %q", in, got, wantStr)
+	}
 }
@@ -0,0 +1,458 @@
+package astra
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Schema provides metadata about the keyspaces, tables, user-defined types
+// and materialized views of the database, read from the system_schema
+// keyspace. Metadata is cached per keyspace, and the cache is invalidated when
+// a query executed by the client reports a schema change. Use Client.Schema to
+// get the Schema of a client.
+type Schema struct {
+	client *Client
+
+	mu        sync.Mutex
+	gen       uint64
+	names     []string
+	keyspaces map[string]*KeyspaceMetadata
+}
+
+// KeyspaceMetadata describes a keyspace.
+type KeyspaceMetadata struct {
+	Name          string
+	DurableWrites bool
+	// Replication is the replication strategy of the keyspace, e.g.
+	// {"class": "org.apache.cassandra.locator.NetworkTopologyStrategy", "dc1": "3"}.
+	Replication map[string]string
+	// Tables are the tables of the keyspace, keyed by name.
+	Tables map[string]*TableMetadata
+	// Types are the user-defined types of the keyspace, keyed by name.
+	Types map[string]*UserTypeMetadata
+	// Views are the materialized views of the keyspace, keyed by name.
+	Views map[string]*ViewMetadata
+}
+
+// TableMetadata describes a table.
+type TableMetadata struct {
+	Keyspace string
+	Name     string
+	// PartitionKey are the partition key columns, in order.
+	PartitionKey []*ColumnMetadata
+	// ClusteringColumns are the clustering columns, in order.
+	ClusteringColumns []*ColumnMetadata
+	// Columns are all columns of the table, keyed by name.
+	Columns map[string]*ColumnMetadata
+	// Indexes are the secondary indexes of the table, keyed by name.
+	Indexes map[string]*IndexMetadata
+	// Options are the table options, e.g. "default_time_to_live", keyed by
+	// name as in system_schema.tables.
+	Options map[string]any
+}
+
+// ColumnKind is the kind of a table column.
+type ColumnKind string
+
+// Column kinds.
+const (
+	ColumnPartitionKey ColumnKind = "partition_key"
+	ColumnClustering   ColumnKind = "clustering"
+	ColumnRegular      ColumnKind = "regular"
+	ColumnStatic       ColumnKind = "static"
+)
+
+// ClusteringOrder is the order of a clustering column.
+type ClusteringOrder string
+
+// Clustering orders. Columns other than clustering columns have order
+// ClusteringNone.
+const (
+	ClusteringAsc  ClusteringOrder = "asc"
+	ClusteringDesc ClusteringOrder = "desc"
+	ClusteringNone ClusteringOrder = "none"
+)
+
+// ColumnMetadata describes a table or materialized view column.
+type ColumnMetadata struct {
+	Name string
+	Kind ColumnKind
+	// Type is the CQL type of the column.
+	Type *CQLType
+	// Position is the position of a partition key or clustering column within
+	// the key, or -1 for other columns.
+	Position        int
+	ClusteringOrder ClusteringOrder
+}
+
+// IndexMetadata describes a secondary index.
+type IndexMetadata struct {
+	Name string
+	// Kind is the kind of index, e.g. "COMPOSITES" or "CUSTOM".
+	Kind string
+	// Options are the index options, e.g. {"target": "email"}.
+	Options map[string]string
+}
+
+// UserTypeMetadata describes a user-defined type.
+type UserTypeMetadata struct {
+	Keyspace string
+	Name     string
+	// FieldNames are the names of the fields of the type, in order.
+	FieldNames []string
+	// FieldTypes are the CQL types of the fields of the type, in order.
+	FieldTypes []*CQLType
+}
+
+// ViewMetadata describes a materialized view.
+type ViewMetadata struct {
+	Keyspace          string
+	Name              string
+	BaseTable         string
+	IncludeAllColumns bool
+	WhereClause       string
+	// PartitionKey are the partition key columns, in order.
+	PartitionKey []*ColumnMetadata
+	// ClusteringColumns are the clustering columns, in order.
+	ClusteringColumns []*ColumnMetadata
+	// Columns are all columns of the view, keyed by name.
+	Columns map[string]*ColumnMetadata
+}
+
+// Schema returns the Schema of the database the client is connected to.
+func (c *Client) Schema() *Schema {
+	c.schemaOnce.Do(func() {
+		c.schema = &Schema{
+			client:    c,
+			keyspaces: map[string]*KeyspaceMetadata{},
+		}
+	})
+	return c.schema
+}
+
+// Keyspaces returns the names of all keyspaces, in order.
+func (s *Schema) Keyspaces() ([]string, error) {
+	return s.KeyspacesContext(context.Background())
+}
+
+// KeyspacesContext is like Keyspaces, but uses the provided context for the
+// requests.
+func (s *Schema) KeyspacesContext(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	names, gen := s.names, s.gen
+	s.mu.Unlock()
+	if names != nil {
+		return append([]string(nil), names...), nil
+	}
+
+	rows, err := s.client.Query(`SELECT keyspace_name FROM system_schema.keyspaces`).ExecContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyspaces: %w", err)
+	}
+	names = make([]string, 0, len(rows))
+	for _, r := range rows {
+		var name string
+		if err := r.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to read keyspaces: %w", err)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	s.mu.Lock()
+	if s.gen == gen {
+		s.names = names
+	}
+	s.mu.Unlock()
+	return append([]string(nil), names...), nil
+}
+
+// Keyspace returns the metadata of the named keyspace.
+func (s *Schema) Keyspace(name string) (*KeyspaceMetadata, error) {
+	return s.KeyspaceContext(context.Background(), name)
+}
+
+// KeyspaceContext is like Keyspace, but uses the provided context for the
+// requests.
+func (s *Schema) KeyspaceContext(ctx context.Context, name string) (*KeyspaceMetadata, error) {
+	s.mu.Lock()
+	km, gen := s.keyspaces[name], s.gen
+	s.mu.Unlock()
+	if km != nil {
+		return km, nil
+	}
+
+	km, err := s.loadKeyspace(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata of keyspace %q: %w", name, err)
+	}
+
+	s.mu.Lock()
+	if s.gen == gen {
+		s.keyspaces[name] = km
+	}
+	s.mu.Unlock()
+	return km, nil
+}
+
+// Table returns the metadata of the named table of keyspace.
+func (s *Schema) Table(keyspace, table string) (*TableMetadata, error) {
+	return s.TableContext(context.Background(), keyspace, table)
+}
+
+// TableContext is like Table, but uses the provided context for the
+// requests.
+func (s *Schema) TableContext(ctx context.Context, keyspace, table string) (*TableMetadata, error) {
+	km, err := s.KeyspaceContext(ctx, keyspace)
+	if err != nil {
+		return nil, err
+	}
+	tm, ok := km.Tables[table]
+	if !ok {
+		return nil, fmt.Errorf("table %q not found in keyspace %q", table, keyspace)
+	}
+	return tm, nil
+}
+
+// Invalidate discards the cached metadata of keyspace, or of all keyspaces if
+// keyspace is empty. It is called automatically for schema changes made
+// through the client, and need only be called for changes made elsewhere.
+func (s *Schema) Invalidate(keyspace string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gen++
+	if keyspace == "" {
+		s.names = nil
+		s.keyspaces = map[string]*KeyspaceMetadata{}
+		return
+	}
+	delete(s.keyspaces, keyspace)
+}
+
+// schemaChanged invalidates the metadata affected by a schema change.
+func (s *Schema) schemaChanged(sc *SchemaChange) {
+	s.Invalidate(sc.Keyspace)
+	if sc.Target == TargetKeyspace {
+		s.mu.Lock()
+		s.names = nil
+		s.mu.Unlock()
+	}
+}
+
+// Rows of the system_schema tables, scanned with Row.ScanStruct.
+type (
+	keyspaceRow struct {
+		DurableWrites bool              `cql:"durable_writes"`
+		Replication   map[string]string `cql:"replication"`
+	}
+	columnRow struct {
+		Table           string `cql:"table_name"`
+		Name            string `cql:"column_name"`
+		ClusteringOrder string `cql:"clustering_order"`
+		Kind            string `cql:"kind"`
+		Position        int    `cql:"position"`
+		Type            string `cql:"type"`
+	}
+	indexRow struct {
+		Table   string            `cql:"table_name"`
+		Name    string            `cql:"index_name"`
+		Kind    string            `cql:"kind"`
+		Options map[string]string `cql:"options"`
+	}
+	typeRow struct {
+		Name       string   `cql:"type_name"`
+		FieldNames []string `cql:"field_names"`
+		FieldTypes []string `cql:"field_types"`
+	}
+	viewRow struct {
+		Name              string `cql:"view_name"`
+		BaseTable         string `cql:"base_table_name"`
+		IncludeAllColumns bool   `cql:"include_all_columns"`
+		WhereClause       string `cql:"where_clause"`
+	}
+)
+
+func (s *Schema) loadKeyspace(ctx context.Context, name string) (*KeyspaceMetadata, error) {
+	query := func(cql string) (Rows, error) {
+		return s.client.Query(cql, name).ExecContext(ctx)
+	}
+
+	rows, err := query(`SELECT durable_writes, replication FROM system_schema.keyspaces WHERE keyspace_name = ?`)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("keyspace not found")
+	}
+	ks, err := ScanAll[keyspaceRow](rows)
+	if err != nil {
+		return nil, err
+	}
+	km := &KeyspaceMetadata{
+		Name:          name,
+		DurableWrites: ks[0].DurableWrites,
+		Replication:   ks[0].Replication,
+		Tables:        map[string]*TableMetadata{},
+		Types:         map[string]*UserTypeMetadata{},
+		Views:         map[string]*ViewMetadata{},
+	}
+
+	if rows, err = query(`SELECT type_name, field_names, field_types FROM system_schema.types WHERE keyspace_name = ?`); err != nil {
+		return nil, err
+	}
+	types, err := ScanAll[typeRow](rows)
+	if err != nil {
+		return nil, err
+	}
+	udt, err := resolveTypes(km, types)
+	if err != nil {
+		return nil, err
+	}
+
+	if rows, err = query(`SELECT * FROM system_schema.tables WHERE keyspace_name = ?`); err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		tm := &TableMetadata{
+			Keyspace: name,
+			Columns:  map[string]*ColumnMetadata{},
+			Indexes:  map[string]*IndexMetadata{},
+			Options:  map[string]any{},
+		}
+		for i, col := range r.Columns() {
+			switch col.Name {
+			case "keyspace_name":
+			case "table_name":
+				tm.Name, _ = r.values[i].(string)
+			default:
+				tm.Options[col.Name] = r.values[i]
+			}
+		}
+		km.Tables[tm.Name] = tm
+	}
+
+	if rows, err = query(`SELECT view_name, base_table_name, include_all_columns, where_clause FROM system_schema.views WHERE keyspace_name = ?`); err != nil {
+		return nil, err
+	}
+	views, err := ScanAll[viewRow](rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range views {
+		km.Views[v.Name] = &ViewMetadata{
+			Keyspace:          name,
+			Name:              v.Name,
+			BaseTable:         v.BaseTable,
+			IncludeAllColumns: v.IncludeAllColumns,
+			WhereClause:       v.WhereClause,
+			Columns:           map[string]*ColumnMetadata{},
+		}
+	}
+
+	if rows, err = query(`SELECT table_name, column_name, clustering_order, kind, position, type FROM system_schema.columns WHERE keyspace_name = ?`); err != nil {
+		return nil, err
+	}
+	cols, err := ScanAll[columnRow](rows)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(cols, func(i, j int) bool { return cols[i].Position < cols[j].Position })
+	for _, c := range cols {
+		t, err := parseCQLType(c.Type, udt)
+		if err != nil {
+			return nil, err
+		}
+		cm := &ColumnMetadata{
+			Name:            c.Name,
+			Kind:            ColumnKind(c.Kind),
+			Type:            t,
+			Position:        c.Position,
+			ClusteringOrder: ClusteringOrder(c.ClusteringOrder),
+		}
+		if tm, ok := km.Tables[c.Table]; ok {
+			tm.Columns[c.Name] = cm
+			tm.PartitionKey, tm.ClusteringColumns = appendKeyColumn(tm.PartitionKey, tm.ClusteringColumns, cm)
+		} else if vm, ok := km.Views[c.Table]; ok {
+			vm.Columns[c.Name] = cm
+			vm.PartitionKey, vm.ClusteringColumns = appendKeyColumn(vm.PartitionKey, vm.ClusteringColumns, cm)
+		}
+	}
+
+	if rows, err = query(`SELECT table_name, index_name, kind, options FROM system_schema.indexes WHERE keyspace_name = ?`); err != nil {
+		return nil, err
+	}
+	indexes, err := ScanAll[indexRow](rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, ix := range indexes {
+		if tm, ok := km.Tables[ix.Table]; ok {
+			tm.Indexes[ix.Name] = &IndexMetadata{Name: ix.Name, Kind: ix.Kind, Options: ix.Options}
+		}
+	}
+
+	return km, nil
+}
+
+// appendKeyColumn appends cm to the partition key or clustering columns
+// according to its kind. Columns must be appended in position order.
+func appendKeyColumn(pk, cc []*ColumnMetadata, cm *ColumnMetadata) ([]*ColumnMetadata, []*ColumnMetadata) {
+	switch cm.Kind {
+	case ColumnPartitionKey:
+		pk = append(pk, cm)
+	case ColumnClustering:
+		cc = append(cc, cm)
+	}
+	return pk, cc
+}
+
+// resolveTypes adds the user-defined types to km, and returns a function
+// resolving their CQL types by name. User-defined types may be nested in any
+// order.
+func resolveTypes(km *KeyspaceMetadata, types []typeRow) (func(string) *CQLType, error) {
+	rows := make(map[string]typeRow, len(types))
+	for _, t := range types {
+		rows[t.Name] = t
+	}
+	resolved := map[string]*CQLType{}
+	var resolve func(name string) *CQLType
+	var resolveErr error
+	resolve = func(name string) *CQLType {
+		if t, ok := resolved[name]; ok {
+			return t
+		}
+		row, ok := rows[name]
+		if !ok {
+			return nil
+		}
+		t := &CQLType{Kind: TypeUDT, Fields: make(map[string]*CQLType, len(row.FieldNames))}
+		// Store the type before resolving its fields, so that cyclic
+		// references, which Cassandra does not allow, cannot recurse forever.
+		resolved[name] = t
+		um := &UserTypeMetadata{
+			Keyspace:   km.Name,
+			Name:       name,
+			FieldNames: row.FieldNames,
+			FieldTypes: make([]*CQLType, len(row.FieldTypes)),
+		}
+		for i, ft := range row.FieldTypes {
+			ct, err := parseCQLType(ft, resolve)
+			if err != nil {
+				resolveErr = err
+				return t
+			}
+			um.FieldTypes[i] = ct
+			if i < len(row.FieldNames) {
+				t.Fields[row.FieldNames[i]] = ct
+			}
+		}
+		km.Types[name] = um
+		return t
+	}
+	for _, t := range types {
+		resolve(t.Name)
+	}
+	return resolve, resolveErr
+}
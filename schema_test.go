@@ -0,0 +1,217 @@
+package astra
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var (
+	textSpec = &pb.TypeSpec{Spec: &pb.TypeSpec_Basic_{Basic: pb.TypeSpec_VARCHAR}}
+	boolSpec = &pb.TypeSpec{Spec: &pb.TypeSpec_Basic_{Basic: pb.TypeSpec_BOOLEAN}}
+	intSpec  = &pb.TypeSpec{Spec: &pb.TypeSpec_Basic_{Basic: pb.TypeSpec_INT}}
+	listSpec = &pb.TypeSpec{Spec: &pb.TypeSpec_List_{List: &pb.TypeSpec_List{Element: textSpec}}}
+	mapSpec  = &pb.TypeSpec{Spec: &pb.TypeSpec_Map_{Map: &pb.TypeSpec_Map{Key: textSpec, Value: textSpec}}}
+)
+
+func textProto(s string) *pb.Value { return &pb.Value{Inner: &pb.Value_String_{String_: s}} }
+func boolProto(b bool) *pb.Value   { return &pb.Value{Inner: &pb.Value_Boolean{Boolean: b}} }
+func intProto(i int64) *pb.Value   { return &pb.Value{Inner: &pb.Value_Int{Int: i}} }
+
+func collectionProto(ss ...string) *pb.Value {
+	els := make([]*pb.Value, len(ss))
+	for i, s := range ss {
+		els[i] = textProto(s)
+	}
+	return &pb.Value{Inner: &pb.Value_Collection{Collection: &pb.Collection{Elements: els}}}
+}
+
+// resultSetResponse returns a response with a result set of the given
+// columns, as name and type pairs, and rows.
+func resultSetResponse(cols []string, types []*pb.TypeSpec, rows ...[]*pb.Value) *pb.Response {
+	rs := &pb.ResultSet{}
+	for i, c := range cols {
+		rs.Columns = append(rs.Columns, &pb.ColumnSpec{Name: c, Type: types[i]})
+	}
+	for _, r := range rows {
+		rs.Rows = append(rs.Rows, &pb.Row{Values: r})
+	}
+	return &pb.Response{Result: &pb.Response_ResultSet{ResultSet: rs}}
+}
+
+func schemaHandler(cql string) *pb.Response {
+	switch {
+	case strings.Contains(cql, "system_schema.keyspaces WHERE"):
+		return resultSetResponse(
+			[]string{"durable_writes", "replication"}, []*pb.TypeSpec{boolSpec, mapSpec},
+			[]*pb.Value{boolProto(true), collectionProto("class", "SimpleStrategy", "replication_factor", "1")},
+		)
+	case strings.Contains(cql, "system_schema.keyspaces"):
+		return resultSetResponse(
+			[]string{"keyspace_name"}, []*pb.TypeSpec{textSpec},
+			[]*pb.Value{textProto("system")}, []*pb.Value{textProto("ks")},
+		)
+	case strings.Contains(cql, "system_schema.types"):
+		return resultSetResponse(
+			[]string{"type_name", "field_names", "field_types"}, []*pb.TypeSpec{textSpec, listSpec, listSpec},
+			[]*pb.Value{textProto("location"), collectionProto("home", "zips"), collectionProto("frozen<address>", "set<int>")},
+			[]*pb.Value{textProto("address"), collectionProto("street"), collectionProto("text")},
+		)
+	case strings.Contains(cql, "system_schema.tables"):
+		return resultSetResponse(
+			[]string{"keyspace_name", "table_name", "default_time_to_live"}, []*pb.TypeSpec{textSpec, textSpec, intSpec},
+			[]*pb.Value{textProto("ks"), textProto("users"), intProto(3600)},
+		)
+	case strings.Contains(cql, "system_schema.views"):
+		return resultSetResponse(
+			[]string{"view_name", "base_table_name", "include_all_columns", "where_clause"},
+			[]*pb.TypeSpec{textSpec, textSpec, boolSpec, textSpec},
+			[]*pb.Value{textProto("users_by_name"), textProto("users"), boolProto(false), textProto("name IS NOT NULL")},
+		)
+	case strings.Contains(cql, "system_schema.columns"):
+		col := func(table, name, order, kind string, pos int64, typ string) []*pb.Value {
+			return []*pb.Value{textProto(table), textProto(name), textProto(order), textProto(kind), intProto(pos), textProto(typ)}
+		}
+		return resultSetResponse(
+			[]string{"table_name", "column_name", "clustering_order", "kind", "position", "type"},
+			[]*pb.TypeSpec{textSpec, textSpec, textSpec, textSpec, intSpec, textSpec},
+			col("users", "bucket", "none", "partition_key", 1, "int"),
+			col("users", "id", "none", "partition_key", 0, "uuid"),
+			col("users", "ts", "desc", "clustering", 0, "timestamp"),
+			col("users", "name", "none", "regular", -1, "text"),
+			col("users", "loc", "none", "regular", -1, "frozen<location>"),
+			col("users_by_name", "name", "none", "partition_key", 0, "text"),
+		)
+	case strings.Contains(cql, "system_schema.indexes"):
+		return resultSetResponse(
+			[]string{"table_name", "index_name", "kind", "options"}, []*pb.TypeSpec{textSpec, textSpec, textSpec, mapSpec},
+			[]*pb.Value{textProto("users"), textProto("users_name_idx"), textProto("COMPOSITES"), collectionProto("target", "name")},
+		)
+	case strings.HasPrefix(cql, "ALTER"):
+		return &pb.Response{Result: &pb.Response_SchemaChange{SchemaChange: &pb.SchemaChange{
+			ChangeType: pb.SchemaChange_UPDATED,
+			Target:     pb.SchemaChange_TABLE,
+			Keyspace:   "ks",
+			Name:       &wrapperspb.StringValue{Value: "users"},
+		}}}
+	}
+	return &pb.Response{}
+}
+
+func TestSchema(t *testing.T) {
+	f := &fakeStargateClient{handler: schemaHandler}
+	c := &Client{sgClient: f, timeout: defaultTimeout}
+
+	names, err := c.Schema().Keyspaces()
+	if err != nil {
+		t.Fatalf("Keyspaces() unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"ks", "system"}, names); diff != "" {
+		t.Errorf("Keyspaces() mismatch (-want +got):\n%s", diff)
+	}
+
+	km, err := c.Schema().Keyspace("ks")
+	if err != nil {
+		t.Fatalf("Keyspace() unexpected error: %v", err)
+	}
+	if !km.DurableWrites || km.Replication["class"] != "SimpleStrategy" {
+		t.Errorf("Keyspace() got durable writes %v, replication %v", km.DurableWrites, km.Replication)
+	}
+
+	address := &CQLType{Kind: TypeUDT, Fields: map[string]*CQLType{"street": {Kind: TypeText}}}
+	location := &CQLType{Kind: TypeUDT, Fields: map[string]*CQLType{
+		"home": address,
+		"zips": {Kind: TypeSet, Elem: &CQLType{Kind: TypeInt}},
+	}}
+	if diff := cmp.Diff(&UserTypeMetadata{
+		Keyspace:   "ks",
+		Name:       "location",
+		FieldNames: []string{"home", "zips"},
+		FieldTypes: []*CQLType{address, location.Fields["zips"]},
+	}, km.Types["location"]); diff != "" {
+		t.Errorf("Keyspace() location type mismatch (-want +got):\n%s", diff)
+	}
+
+	tm, err := c.Schema().Table("ks", "users")
+	if err != nil {
+		t.Fatalf("Table() unexpected error: %v", err)
+	}
+	id := &ColumnMetadata{Name: "id", Kind: ColumnPartitionKey, Type: &CQLType{Kind: TypeUUID}, Position: 0, ClusteringOrder: ClusteringNone}
+	bucket := &ColumnMetadata{Name: "bucket", Kind: ColumnPartitionKey, Type: &CQLType{Kind: TypeInt}, Position: 1, ClusteringOrder: ClusteringNone}
+	ts := &ColumnMetadata{Name: "ts", Kind: ColumnClustering, Type: &CQLType{Kind: TypeTimestamp}, Position: 0, ClusteringOrder: ClusteringDesc}
+	want := &TableMetadata{
+		Keyspace:          "ks",
+		Name:              "users",
+		PartitionKey:      []*ColumnMetadata{id, bucket},
+		ClusteringColumns: []*ColumnMetadata{ts},
+		Columns: map[string]*ColumnMetadata{
+			"id":     id,
+			"bucket": bucket,
+			"ts":     ts,
+			"name":   {Name: "name", Kind: ColumnRegular, Type: &CQLType{Kind: TypeText}, Position: -1, ClusteringOrder: ClusteringNone},
+			"loc":    {Name: "loc", Kind: ColumnRegular, Type: location, Position: -1, ClusteringOrder: ClusteringNone},
+		},
+		Indexes: map[string]*IndexMetadata{
+			"users_name_idx": {Name: "users_name_idx", Kind: "COMPOSITES", Options: map[string]string{"target": "name"}},
+		},
+		Options: map[string]any{"default_time_to_live": int64(3600)},
+	}
+	if diff := cmp.Diff(want, tm); diff != "" {
+		t.Errorf("Table() mismatch (-want +got):\n%s", diff)
+	}
+
+	vm := km.Views["users_by_name"]
+	if vm == nil || vm.BaseTable != "users" || len(vm.PartitionKey) != 1 || vm.PartitionKey[0].Name != "name" {
+		t.Errorf("Keyspace() got view %+v", vm)
+	}
+
+	// Cached metadata is returned until a schema change is observed.
+	n := len(f.queries)
+	if _, err := c.Schema().Keyspace("ks"); err != nil || len(f.queries) != n {
+		t.Errorf("Keyspace() got error %v and %d new queries, want cached metadata", err, len(f.queries)-n)
+	}
+	if _, err := c.Query(`ALTER TABLE ks.users ADD email text`).Exec(); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+	n = len(f.queries)
+	if _, err := c.Schema().Keyspace("ks"); err != nil || len(f.queries) == n {
+		t.Errorf("Keyspace() after schema change got error %v and no new queries, want reloaded metadata", err)
+	}
+}
+
+func TestParseCQLType(t *testing.T) {
+	udt := func(name string) *CQLType {
+		if name == "address" {
+			return &CQLType{Kind: TypeUDT}
+		}
+		return nil
+	}
+	tests := map[string]string{
+		"int":                                  "int",
+		"frozen<map<text, list<int>>>":         "map<text, list<int>>",
+		"tuple<int, frozen<set<uuid>>, blob>":  "tuple<int, set<uuid>, blob>",
+		"frozen<address>":                      "udt<>",
+		"duration":                             "custom",
+		"'org.apache.cassandra.db.marshal.X'":  "custom",
+		"map<text,frozen<tuple<int,boolean>>>": "map<text, tuple<int, boolean>>",
+	}
+	for in, want := range tests {
+		got, err := parseCQLType(in, udt)
+		if err != nil {
+			t.Errorf("parseCQLType(%q) unexpected error: %v", in, err)
+			continue
+		}
+		if got.String() != want {
+			t.Errorf("parseCQLType(%q) got %s, want %s", in, got, want)
+		}
+	}
+
+	for _, in := range []string{"", "list<int", "map<int>", "list<int>>", "set<>"} {
+		if _, err := parseCQLType(in, udt); err == nil {
+			t.Errorf("parseCQLType(%q) got nil error, want error", in)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package sqldriver
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/datastax-ext/astra-go-sdk"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// errNoTransactions is returned by Begin, as CQL has no transactions.
+var errNoTransactions = errors.New("transactions are not supported")
+
+// conn is a connection using an astra.Client. It is stateless, so
+// connections of a sql.DB share one client.
+type conn struct {
+	client *astra.Client
+	// closer is closed with the connection, if set.
+	closer io.Closer
+}
+
+var (
+	_ driver.Conn               = (*conn)(nil)
+	_ driver.ConnPrepareContext = (*conn)(nil)
+	_ driver.ExecerContext      = (*conn)(nil)
+	_ driver.QueryerContext     = (*conn)(nil)
+	_ driver.Pinger             = (*conn)(nil)
+	_ driver.NamedValueChecker  = (*conn)(nil)
+)
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext returns a statement executing query. Queries are not
+// prepared on the server.
+func (c *conn) PrepareContext(_ context.Context, query string) (driver.Stmt, error) {
+	return &stmt{conn: c, cql: query}, nil
+}
+
+func (c *conn) Close() error {
+	if c.closer == nil {
+		return nil
+	}
+	return c.closer.Close()
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return nil, errNoTransactions
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	q, err := c.query(query, args)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := q.ExecResultContext(ctx); err != nil {
+		return nil, err
+	}
+	return driver.ResultNoRows, nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, err := c.query(query, args)
+	if err != nil {
+		return nil, err
+	}
+	res, err := q.ExecResultContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newRows(ctx, q, res), nil
+}
+
+// Ping checks that the server can be queried.
+func (c *conn) Ping(ctx context.Context) error {
+	_, err := c.client.Query("SELECT release_version FROM system.local").ExecResultContext(ctx)
+	return err
+}
+
+// CheckNamedValue accepts values of any type, leaving their conversion to
+// the astra.Client. Values implementing driver.Valuer are converted by
+// database/sql, except for UUIDs and decimals, which the astra.Client
+// encodes natively rather than as the strings returned by their Value
+// methods.
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	switch nv.Value.(type) {
+	case uuid.UUID, *uuid.UUID, decimal.Decimal, *decimal.Decimal:
+		return nil
+	}
+	if _, ok := nv.Value.(driver.Valuer); ok {
+		return driver.ErrSkip
+	}
+	return nil
+}
+
+// query creates a query for cql with the values of args bound to its
+// positional markers or, if args are named, its named markers.
+func (c *conn) query(cql string, args []driver.NamedValue) (*astra.Query, error) {
+	if len(args) == 0 || args[0].Name == "" {
+		values := make([]any, len(args))
+		for i, arg := range args {
+			if arg.Name != "" {
+				return nil, fmt.Errorf("cannot mix named and positional arguments")
+			}
+			values[i] = arg.Value
+		}
+		return c.client.Query(cql, values...), nil
+	}
+	named := make(map[string]any, len(args))
+	for _, arg := range args {
+		if arg.Name == "" {
+			return nil, fmt.Errorf("cannot mix named and positional arguments")
+		}
+		named[arg.Name] = arg.Value
+	}
+	return c.client.Query(cql).Bind(named), nil
+}
+
+// stmt is a statement of a conn. The number of its arguments is not checked
+// by database/sql, as it is not known until the statement is executed.
+type stmt struct {
+	conn *conn
+	cql  string
+}
+
+var (
+	_ driver.Stmt              = (*stmt)(nil)
+	_ driver.StmtExecContext   = (*stmt)(nil)
+	_ driver.StmtQueryContext  = (*stmt)(nil)
+	_ driver.NamedValueChecker = (*stmt)(nil)
+)
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.cql, args)
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.cql, args)
+}
+
+func (s *stmt) CheckNamedValue(nv *driver.NamedValue) error {
+	return s.conn.CheckNamedValue(nv)
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	nvs := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		nvs[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return nvs
+}
@@ -0,0 +1,35 @@
+package sqldriver
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
+)
+
+func TestConn_bindValuers(t *testing.T) {
+	db, fake := openTestDB(t, &pb.Response{})
+	id := uuid.MustParse("c5e3a0a2-8b27-4a59-9b0c-7b1b2f6a7d3e")
+	price := decimal.New(1250, -2)
+
+	if _, err := db.Exec("INSERT INTO items (id, price) VALUES (?, ?)", id, price); err != nil {
+		t.Fatalf("Exec() unexpected error: %v", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.queries) != 1 {
+		t.Fatalf("got %d queries, want 1", len(fake.queries))
+	}
+	values := fake.queries[0].GetValues().GetValues()
+	if len(values) != 2 {
+		t.Fatalf("got %d values, want 2", len(values))
+	}
+	if got, ok := values[0].Inner.(*pb.Value_Uuid); !ok || string(got.Uuid.Value) != string(id[:]) {
+		t.Errorf("bound UUID got %T %v, want *pb.Value_Uuid of %v", values[0].Inner, values[0].Inner, id)
+	}
+	if got, ok := values[1].Inner.(*pb.Value_Decimal); !ok || got.Decimal.Scale != 2 {
+		t.Errorf("bound decimal got %T %v, want *pb.Value_Decimal with scale 2", values[1].Inner, values[1].Inner)
+	}
+}
@@ -0,0 +1,136 @@
+// Package sqldriver provides a database/sql driver for Astra, registered as
+// "astra".
+//
+//	db, err := sql.Open("astra", "astra://?token=AstraCS%3A...&scb=/path/to/bundle.zip&keyspace=example")
+//	if err != nil {
+//	    // Handle error.
+//	}
+//	defer db.Close()
+//
+//	var name string
+//	err = db.QueryRowContext(ctx, "SELECT name FROM users WHERE id = ?", id).Scan(&name)
+//
+// See ParseDSN for the DSN format. Use NewConnector with sql.OpenDB to use an
+// existing astra.Client instead.
+//
+// Queries take positional bind markers ("?"), or named bind markers (":name")
+// whose values are passed with sql.Named. Argument values of any type
+// supported by astra.Client are accepted, including UUIDs, collections and
+// UDTs. Values are returned as read by astra.Client, except that timestamp
+// and date columns are returned as a time.Time, and UUID and decimal columns
+// as strings, which scan into a uuid.UUID and a decimal.Decimal. The database
+// type names of columns are their upper case CQL types, e.g. "MAP<TEXT, INT>".
+//
+// CQL has no transactions, so Begin returns an error, and results of
+// statements which return no rows report neither the last insert ID nor the
+// number of rows affected.
+package sqldriver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+
+	"github.com/datastax-ext/astra-go-sdk"
+)
+
+func init() {
+	sql.Register("astra", Driver{})
+}
+
+// Driver is the Astra database/sql driver.
+type Driver struct{}
+
+// Open returns a new connection described by the DSN, with its own
+// astra.Client. The database/sql package uses OpenConnector instead, sharing
+// one astra.Client between all connections of a sql.DB.
+func (d Driver) Open(dsn string) (driver.Conn, error) {
+	c, err := d.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	cn, err := c.Connect(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	cn.(*conn).closer = c.(*connector)
+	return cn, nil
+}
+
+// OpenConnector returns a connector for the DSN. The connector creates an
+// astra.Client on first use, which is closed when the sql.DB is closed.
+func (d Driver) OpenConnector(dsn string) (driver.Connector, error) {
+	cfg, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &connector{cfg: cfg}, nil
+}
+
+// NewConnector returns a connector using the client c, for use with
+// sql.OpenDB. The client is not closed when the sql.DB is closed.
+//
+//	db := sql.OpenDB(sqldriver.NewConnector(c))
+func NewConnector(c *astra.Client) driver.Connector {
+	return &connector{client: c}
+}
+
+type connector struct {
+	cfg *Config
+
+	mu     sync.Mutex
+	client *astra.Client
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client == nil {
+		client, err := newClient(ctx, c.cfg)
+		if err != nil {
+			return nil, err
+		}
+		c.client = client
+	}
+	return &conn{client: c.client}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return Driver{}
+}
+
+// Close closes the client created by the connector, if any.
+func (c *connector) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cfg == nil || c.client == nil {
+		return nil
+	}
+	err := c.client.Close()
+	c.client = nil
+	return err
+}
+
+func newClient(ctx context.Context, cfg *Config) (*astra.Client, error) {
+	connection := astra.WithAstraURI(cfg.URI)
+	if cfg.SecureConnectBundle != "" {
+		connection = astra.WithSecureConnectBundle(cfg.SecureConnectBundle)
+	}
+	var opts []astra.ClientOption
+	if cfg.Keyspace != "" {
+		opts = append(opts, astra.WithDefaultKeyspace(cfg.Keyspace))
+	}
+	if cfg.Timeout != 0 {
+		opts = append(opts, astra.WithTimeout(cfg.Timeout))
+	}
+	if cfg.Insecure {
+		opts = append(opts, astra.WithInsecure(true))
+	}
+	c, err := astra.NewStaticTokenClientContext(ctx, cfg.Token, connection, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	return c, nil
+}
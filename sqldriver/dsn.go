@@ -0,0 +1,98 @@
+package sqldriver
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Config is the connection configuration described by a DSN.
+type Config struct {
+	// URI is the host and port of the Stargate gRPC service, e.g.
+	// "<cluster ID>-<cluster region>.apps.astra.datastax.com:443". It is
+	// ignored if SecureConnectBundle is set.
+	URI string
+	// Token is the static auth token used for requests.
+	Token string
+	// SecureConnectBundle is the path of the secure connect bundle from which
+	// to read the URI and TLS configuration.
+	SecureConnectBundle string
+	// Keyspace is the default keyspace of queries.
+	Keyspace string
+	// Timeout is the timeout of queries. If zero, the client default is used.
+	Timeout time.Duration
+	// Insecure specifies whether to use an insecure connection. Intended for
+	// localhost testing only.
+	Insecure bool
+}
+
+// ParseDSN parses a DSN of the form
+//
+//	astra://[host:port]?token=<token>[&scb=<path>][&keyspace=<keyspace>][&timeout=<duration>][&insecure=<bool>]
+//
+// Either the host and port or the scb parameter, the path of a secure connect
+// bundle, must be given. Parameter values must be URL-encoded.
+func ParseDSN(dsn string) (*Config, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DSN: %w", err)
+	}
+	if u.Scheme != "astra" {
+		return nil, fmt.Errorf("invalid DSN scheme %q, want \"astra\"", u.Scheme)
+	}
+	if u.User != nil || (u.Path != "" && u.Path != "/") {
+		return nil, fmt.Errorf("invalid DSN: unexpected user info or path")
+	}
+
+	cfg := &Config{URI: u.Host}
+	for k, vs := range u.Query() {
+		v := vs[len(vs)-1]
+		switch k {
+		case "token":
+			cfg.Token = v
+		case "scb":
+			cfg.SecureConnectBundle = v
+		case "keyspace":
+			cfg.Keyspace = v
+		case "timeout":
+			if cfg.Timeout, err = time.ParseDuration(v); err != nil {
+				return nil, fmt.Errorf("invalid DSN timeout %q: %w", v, err)
+			}
+		case "insecure":
+			if cfg.Insecure, err = strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("invalid DSN insecure value %q: %w", v, err)
+			}
+		default:
+			return nil, fmt.Errorf("invalid DSN: unknown parameter %q", k)
+		}
+	}
+
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("invalid DSN: missing token")
+	}
+	if (cfg.URI == "") == (cfg.SecureConnectBundle == "") {
+		return nil, fmt.Errorf("invalid DSN: exactly one of host and scb must be given")
+	}
+	return cfg, nil
+}
+
+// FormatDSN returns the DSN describing the configuration, as parsed by
+// ParseDSN.
+func (cfg *Config) FormatDSN() string {
+	q := url.Values{}
+	q.Set("token", cfg.Token)
+	if cfg.SecureConnectBundle != "" {
+		q.Set("scb", cfg.SecureConnectBundle)
+	}
+	if cfg.Keyspace != "" {
+		q.Set("keyspace", cfg.Keyspace)
+	}
+	if cfg.Timeout != 0 {
+		q.Set("timeout", cfg.Timeout.String())
+	}
+	if cfg.Insecure {
+		q.Set("insecure", "true")
+	}
+	return "astra://" + cfg.URI + "?" + q.Encode()
+}
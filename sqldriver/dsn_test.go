@@ -0,0 +1,61 @@
+package sqldriver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseDSN(t *testing.T) {
+	tests := []struct {
+		name    string
+		dsn     string
+		want    *Config
+		wantErr bool
+	}{
+		{
+			name: "uri",
+			dsn:  "astra://localhost:8090?token=AstraCS%3Aabc&keyspace=example&timeout=5s&insecure=true",
+			want: &Config{
+				URI:      "localhost:8090",
+				Token:    "AstraCS:abc",
+				Keyspace: "example",
+				Timeout:  5 * time.Second,
+				Insecure: true,
+			},
+		},
+		{
+			name: "secure connect bundle",
+			dsn:  "astra://?token=t&scb=%2Fpath%2Fto%2Fbundle.zip",
+			want: &Config{Token: "t", SecureConnectBundle: "/path/to/bundle.zip"},
+		},
+		{name: "wrong scheme", dsn: "cql://localhost:8090?token=t", wantErr: true},
+		{name: "missing token", dsn: "astra://localhost:8090", wantErr: true},
+		{name: "missing host", dsn: "astra://?token=t", wantErr: true},
+		{name: "host and scb", dsn: "astra://localhost:8090?token=t&scb=b.zip", wantErr: true},
+		{name: "bad timeout", dsn: "astra://localhost:8090?token=t&timeout=5", wantErr: true},
+		{name: "unknown parameter", dsn: "astra://localhost:8090?token=t&consistency=ONE", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDSN(tt.dsn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDSN() got error %v, want error %t", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseDSN() mismatch (-want +got):\n%s", diff)
+			}
+			if got == nil {
+				return
+			}
+			again, err := ParseDSN(got.FormatDSN())
+			if err != nil {
+				t.Fatalf("ParseDSN(FormatDSN()) unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(got, again); diff != "" {
+				t.Errorf("ParseDSN(FormatDSN()) mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
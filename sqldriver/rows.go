@@ -0,0 +1,134 @@
+package sqldriver
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"math/big"
+	"net"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/datastax-ext/astra-go-sdk"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// rows iterates over the rows of a query, fetching subsequent pages as
+// needed.
+type rows struct {
+	ctx     context.Context
+	query   *astra.Query
+	columns []astra.Column
+
+	page []astra.Row
+	pos  int
+	next []byte
+}
+
+var (
+	_ driver.Rows                           = (*rows)(nil)
+	_ driver.RowsColumnTypeDatabaseTypeName = (*rows)(nil)
+	_ driver.RowsColumnTypeScanType         = (*rows)(nil)
+)
+
+func newRows(ctx context.Context, q *astra.Query, res *astra.Result) *rows {
+	return &rows{
+		ctx:     ctx,
+		query:   q,
+		columns: res.Columns,
+		page:    res.Rows,
+		next:    res.PagingState,
+	}
+}
+
+func (r *rows) Columns() []string {
+	names := make([]string, len(r.columns))
+	for i, col := range r.columns {
+		names[i] = col.Name
+	}
+	return names
+}
+
+func (r *rows) Close() error {
+	r.page, r.next = nil, nil
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	for r.pos >= len(r.page) {
+		if len(r.next) == 0 {
+			return io.EOF
+		}
+		res, err := r.query.PagingState(r.next).ExecResultContext(r.ctx)
+		if err != nil {
+			return err
+		}
+		r.page, r.pos, r.next = res.Rows, 0, res.PagingState
+	}
+	for i, v := range r.page[r.pos].Values() {
+		dest[i] = driverValue(v, r.columns[i].Type)
+	}
+	r.pos++
+	return nil
+}
+
+// ColumnTypeDatabaseTypeName returns the upper case CQL type of the column,
+// e.g. "LIST<INT>".
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	return strings.ToUpper(r.columns[index].Type.String())
+}
+
+// ColumnTypeScanType returns the type of the values of the column.
+func (r *rows) ColumnTypeScanType(index int) reflect.Type {
+	if t, ok := scanTypes[r.columns[index].Type.Kind]; ok {
+		return t
+	}
+	return reflect.TypeOf((*any)(nil)).Elem()
+}
+
+// driverValue returns the value v of a column of type t as returned by rows.
+// UUIDs and decimals are returned as strings, since their Scan methods, which
+// database/sql calls when scanning into them, do not accept their own types.
+func driverValue(v any, t *astra.CQLType) driver.Value {
+	switch v := v.(type) {
+	case int64:
+		if t.Kind == astra.TypeTimestamp {
+			return time.UnixMilli(v).UTC()
+		}
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case uuid.UUID:
+		return v.String()
+	case decimal.Decimal:
+		return v.String()
+	}
+	return v
+}
+
+var scanTypes = map[astra.TypeKind]reflect.Type{
+	astra.TypeASCII:     reflect.TypeOf(""),
+	astra.TypeBigInt:    reflect.TypeOf(int64(0)),
+	astra.TypeBlob:      reflect.TypeOf([]byte(nil)),
+	astra.TypeBoolean:   reflect.TypeOf(false),
+	astra.TypeCounter:   reflect.TypeOf(int64(0)),
+	astra.TypeDecimal:   reflect.TypeOf(""),
+	astra.TypeDouble:    reflect.TypeOf(float64(0)),
+	astra.TypeFloat:     reflect.TypeOf(float32(0)),
+	astra.TypeInt:       reflect.TypeOf(int64(0)),
+	astra.TypeText:      reflect.TypeOf(""),
+	astra.TypeTimestamp: reflect.TypeOf(time.Time{}),
+	astra.TypeUUID:      reflect.TypeOf(""),
+	astra.TypeVarchar:   reflect.TypeOf(""),
+	astra.TypeVarint:    reflect.TypeOf((*big.Int)(nil)),
+	astra.TypeTimeUUID:  reflect.TypeOf(""),
+	astra.TypeInet:      reflect.TypeOf(net.IP(nil)),
+	astra.TypeDate:      reflect.TypeOf(time.Time{}),
+	astra.TypeTime:      reflect.TypeOf(time.Duration(0)),
+	astra.TypeSmallInt:  reflect.TypeOf(int64(0)),
+	astra.TypeTinyInt:   reflect.TypeOf(int64(0)),
+}
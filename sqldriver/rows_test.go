@@ -0,0 +1,148 @@
+package sqldriver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/datastax-ext/astra-go-sdk"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
+	"google.golang.org/grpc"
+)
+
+func TestRows_columnTypes(t *testing.T) {
+	r := &rows{columns: []astra.Column{
+		{Name: "id", Type: &astra.CQLType{Kind: astra.TypeUUID}},
+		{Name: "scores", Type: &astra.CQLType{
+			Kind: astra.TypeMap,
+			Key:  &astra.CQLType{Kind: astra.TypeText},
+			Elem: &astra.CQLType{Kind: astra.TypeInt},
+		}},
+	}}
+
+	if got, want := r.Columns(), []string{"id", "scores"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Columns() got %v, want %v", got, want)
+	}
+	if got, want := r.ColumnTypeDatabaseTypeName(1), "MAP<TEXT, INT>"; got != want {
+		t.Errorf("ColumnTypeDatabaseTypeName() got %q, want %q", got, want)
+	}
+	if got, want := r.ColumnTypeScanType(0).String(), "string"; got != want {
+		t.Errorf("ColumnTypeScanType(0) got %s, want %s", got, want)
+	}
+	if got, want := r.ColumnTypeScanType(1).String(), "interface {}"; got != want {
+		t.Errorf("ColumnTypeScanType(1) got %s, want %s", got, want)
+	}
+}
+
+func TestDriverValue(t *testing.T) {
+	date := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		v    any
+		kind astra.TypeKind
+		want driver.Value
+	}{
+		{"bigint", int64(1654041600000), astra.TypeBigInt, int64(1654041600000)},
+		{"timestamp", int64(1654041600000), astra.TypeTimestamp, date},
+		{"date", &date, astra.TypeDate, date},
+		{"null date", (*time.Time)(nil), astra.TypeDate, nil},
+		{"text", "a", astra.TypeText, "a"},
+		{"uuid", uuid.MustParse("c5e3a0a2-8b27-4a59-9b0c-7b1b2f6a7d3e"), astra.TypeUUID, "c5e3a0a2-8b27-4a59-9b0c-7b1b2f6a7d3e"},
+		{"decimal", decimal.New(1250, -2), astra.TypeDecimal, "12.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := driverValue(tt.v, &astra.CQLType{Kind: tt.kind})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("driverValue() got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeStargate is a Stargate server responding to every query with resp, and
+// recording the queries it executes.
+type fakeStargate struct {
+	pb.UnimplementedStargateServer
+	resp *pb.Response
+
+	mu      sync.Mutex
+	queries []*pb.Query
+}
+
+func (s *fakeStargate) ExecuteQuery(_ context.Context, q *pb.Query) (*pb.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queries = append(s.queries, q)
+	return s.resp, nil
+}
+
+// openTestDB returns a sql.DB connected to a new fakeStargate responding with
+// resp.
+func openTestDB(t *testing.T, resp *pb.Response) (*sql.DB, *fakeStargate) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	fake := &fakeStargate{resp: resp}
+	srv := grpc.NewServer()
+	pb.RegisterStargateServer(srv, fake)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	db, err := sql.Open("astra", (&Config{URI: lis.Addr().String(), Token: "token", Insecure: true}).FormatDSN())
+	if err != nil {
+		t.Fatalf("sql.Open() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, fake
+}
+
+func TestRows_scan(t *testing.T) {
+	id := uuid.MustParse("c5e3a0a2-8b27-4a59-9b0c-7b1b2f6a7d3e")
+	ts := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	basic := func(t pb.TypeSpec_Basic) *pb.TypeSpec {
+		return &pb.TypeSpec{Spec: &pb.TypeSpec_Basic_{Basic: t}}
+	}
+	db, _ := openTestDB(t, &pb.Response{Result: &pb.Response_ResultSet{ResultSet: &pb.ResultSet{
+		Columns: []*pb.ColumnSpec{
+			{Name: "id", Type: basic(pb.TypeSpec_UUID)},
+			{Name: "price", Type: basic(pb.TypeSpec_DECIMAL)},
+			{Name: "created", Type: basic(pb.TypeSpec_TIMESTAMP)},
+		},
+		Rows: []*pb.Row{{Values: []*pb.Value{
+			{Inner: &pb.Value_Uuid{Uuid: &pb.Uuid{Value: id[:]}}},
+			{Inner: &pb.Value_Decimal{Decimal: &pb.Decimal{Scale: 2, Value: []byte{0x04, 0xe2}}}},
+			{Inner: &pb.Value_Int{Int: ts.UnixMilli()}},
+		}}},
+	}}})
+
+	var (
+		gotID      uuid.UUID
+		gotPrice   decimal.Decimal
+		gotCreated time.Time
+	)
+	err := db.QueryRow("SELECT id, price, created FROM items").Scan(&gotID, &gotPrice, &gotCreated)
+	if err != nil {
+		t.Fatalf("Scan() unexpected error: %v", err)
+	}
+	if gotID != id || !gotPrice.Equal(decimal.New(1250, -2)) || !gotCreated.Equal(ts) {
+		t.Errorf("Scan() got (%v, %v, %v), want (%v, %v, %v)", gotID, gotPrice, gotCreated, id, "12.50", ts)
+	}
+
+	var idStr, priceStr string
+	if err := db.QueryRow("SELECT id, price, created FROM items").Scan(&idStr, &priceStr, &gotCreated); err != nil {
+		t.Fatalf("Scan() into strings unexpected error: %v", err)
+	}
+	if idStr != id.String() || priceStr != "12.5" {
+		t.Errorf("Scan() into strings got (%q, %q), want (%q, %q)", idStr, priceStr, id.String(), "12.5")
+	}
+}
@@ -0,0 +1,50 @@
+package astra
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// TimestampGenerator generates client-side write timestamps, in microseconds
+// since the Unix epoch. Use WithTimestampGenerator to install one on a Client.
+// Implementations must be safe for concurrent use.
+type TimestampGenerator interface {
+	Next() int64
+}
+
+// MonotonicTimestampGenerator generates timestamps from the system clock which
+// strictly increase across calls, even if the clock goes backwards or several
+// timestamps are generated within the same microsecond. The zero value is
+// ready to use.
+type MonotonicTimestampGenerator struct {
+	last int64
+	now  func() time.Time
+}
+
+// NewMonotonicTimestampGenerator creates a new MonotonicTimestampGenerator.
+func NewMonotonicTimestampGenerator() *MonotonicTimestampGenerator {
+	return &MonotonicTimestampGenerator{now: time.Now}
+}
+
+// Next returns the current time in microseconds, or one more than the last
+// returned timestamp if that is not greater.
+func (g *MonotonicTimestampGenerator) Next() int64 {
+	for {
+		now := g.clock().UnixMicro()
+		last := atomic.LoadInt64(&g.last)
+		next := now
+		if next <= last {
+			next = last + 1
+		}
+		if atomic.CompareAndSwapInt64(&g.last, last, next) {
+			return next
+		}
+	}
+}
+
+func (g *MonotonicTimestampGenerator) clock() time.Time {
+	if g.now != nil {
+		return g.now()
+	}
+	return time.Now()
+}
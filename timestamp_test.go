@@ -0,0 +1,85 @@
+package astra
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMonotonicTimestampGenerator(t *testing.T) {
+	now := time.UnixMicro(1000)
+	g := NewMonotonicTimestampGenerator()
+	g.now = func() time.Time { return now }
+
+	if got := g.Next(); got != 1000 {
+		t.Errorf("Next() got %d, want 1000", got)
+	}
+	if got := g.Next(); got != 1001 {
+		t.Errorf("Next() within the same microsecond got %d, want 1001", got)
+	}
+	now = time.UnixMicro(900)
+	if got := g.Next(); got != 1002 {
+		t.Errorf("Next() after clock went backwards got %d, want 1002", got)
+	}
+	now = time.UnixMicro(2000)
+	if got := g.Next(); got != 2000 {
+		t.Errorf("Next() after clock advanced got %d, want 2000", got)
+	}
+}
+
+func TestMonotonicTimestampGenerator_zero(t *testing.T) {
+	var g MonotonicTimestampGenerator
+	before := time.Now().UnixMicro()
+	first, second := g.Next(), g.Next()
+	if first < before || second <= first {
+		t.Errorf("Next() of zero value got %d then %d, want increasing timestamps from %d", first, second, before)
+	}
+}
+
+func TestMonotonicTimestampGenerator_concurrent(t *testing.T) {
+	g := NewMonotonicTimestampGenerator()
+	var (
+		mu   sync.Mutex
+		seen = map[int64]bool{}
+		wg   sync.WaitGroup
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				ts := g.Next()
+				mu.Lock()
+				if seen[ts] {
+					t.Errorf("Next() returned duplicate timestamp %d", ts)
+				}
+				seen[ts] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestClient_requestParams_timestamp(t *testing.T) {
+	c := &Client{}
+	WithTimestampGenerator(fixedTimestampGenerator(42))(c)
+
+	if got := c.requestParams(nil); got.timestamp == nil || *got.timestamp != 42 {
+		t.Errorf("requestParams() got timestamp %v, want 42", got.timestamp)
+	}
+
+	q := c.Query(`INSERT INTO users (id) VALUES (1)`).WithTimestampMicros(7)
+	if got := c.requestParams(q.params); *got.timestamp != 7 {
+		t.Errorf("requestParams() with explicit timestamp got %d, want 7", *got.timestamp)
+	}
+	if *q.params.timestamp != 7 {
+		t.Errorf("requestParams() modified query params")
+	}
+}
+
+type fixedTimestampGenerator int64
+
+func (g fixedTimestampGenerator) Next() int64 {
+	return int64(g)
+}
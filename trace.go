@@ -0,0 +1,66 @@
+package astra
+
+import (
+	"time"
+
+	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
+)
+
+// Trace is the server-side trace of a query, recorded when tracing is enabled
+// with Query.Trace.
+type Trace struct {
+	// ID identifies the trace session, as stored in system_traces.sessions.
+	ID string
+	// Coordinator is the host which coordinated the query, i.e. the source of
+	// the first trace event.
+	Coordinator string
+	// StartedAt is the server-side time the query started, or the zero time
+	// if unknown.
+	StartedAt time.Time
+	// Duration is the server-side duration of the query.
+	Duration time.Duration
+	// Events are the trace events, in the order they were recorded.
+	Events []TraceEvent
+}
+
+// TraceEvent is a single event of a Trace.
+type TraceEvent struct {
+	// ID identifies the event, as stored in system_traces.events.
+	ID string
+	// Activity describes what the source was doing.
+	Activity string
+	// Source is the host which recorded the event.
+	Source string
+	// SourceElapsed is the time elapsed on the source since it started
+	// handling the query.
+	SourceElapsed time.Duration
+	// Thread is the name of the server thread which recorded the event.
+	Thread string
+}
+
+func newTrace(t *pb.Traces) *Trace {
+	if t == nil {
+		return nil
+	}
+	res := &Trace{
+		ID:       t.GetId(),
+		Duration: time.Duration(t.GetDuration()) * time.Microsecond,
+		Events:   make([]TraceEvent, len(t.GetEvents())),
+	}
+	if t.GetStartedAt() != 0 {
+		res.StartedAt = time.UnixMilli(t.GetStartedAt())
+	}
+	for i, e := range t.GetEvents() {
+		res.Events[i] = TraceEvent{
+			ID:            e.GetEventId(),
+			Activity:      e.GetActivity(),
+			Source:        e.GetSource(),
+			SourceElapsed: time.Duration(e.GetSourceElapsed()) * time.Microsecond,
+			Thread:        e.GetThread(),
+		}
+	}
+	if len(res.Events) > 0 {
+		res.Coordinator = res.Events[0].Source
+	}
+	return res
+}
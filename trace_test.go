@@ -0,0 +1,45 @@
+package astra
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
+)
+
+func TestIter_Trace(t *testing.T) {
+	f := &fakeStargateClient{resp: &pb.Response{
+		Traces: &pb.Traces{
+			Id:        "6a8b4e50-0f4a-11ed-8a7c-5f1b4d1c2a3e",
+			Duration:  1500,
+			StartedAt: 1659312000000,
+			Events: []*pb.Traces_Event{
+				{Activity: "Parsing SELECT", Source: "10.0.0.1", SourceElapsed: 100, Thread: "Native-1", EventId: "e1"},
+				{Activity: "Read 1 live rows", Source: "10.0.0.2", SourceElapsed: 900, Thread: "ReadStage-2", EventId: "e2"},
+			},
+		},
+	}}
+	c := &Client{sgClient: f, timeout: defaultTimeout}
+
+	it := c.Query(`SELECT * FROM users`).Trace(true).Iter()
+	for it.Next() {
+	}
+	if err := it.Close(); err != nil {
+		t.Fatalf("Iter.Close() unexpected error: %v", err)
+	}
+
+	want := &Trace{
+		ID:          "6a8b4e50-0f4a-11ed-8a7c-5f1b4d1c2a3e",
+		Coordinator: "10.0.0.1",
+		StartedAt:   time.UnixMilli(1659312000000),
+		Duration:    1500 * time.Microsecond,
+		Events: []TraceEvent{
+			{ID: "e1", Activity: "Parsing SELECT", Source: "10.0.0.1", SourceElapsed: 100 * time.Microsecond, Thread: "Native-1"},
+			{ID: "e2", Activity: "Read 1 live rows", Source: "10.0.0.2", SourceElapsed: 900 * time.Microsecond, Thread: "ReadStage-2"},
+		},
+	}
+	if diff := cmp.Diff(want, it.Trace()); diff != "" {
+		t.Errorf("Iter.Trace() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,326 @@
+package astra
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/datastax-ext/astra-go-sdk/internal/cqlscan"
+	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
+)
+
+// TypeKind identifies the kind of a CQL type.
+type TypeKind uint8
+
+// CQL type kinds.
+// See https://docs.datastax.com/en/cql-oss/3.x/cql/cql_reference/cql_data_types_c.html
+const (
+	TypeCustom TypeKind = iota
+	TypeASCII
+	TypeBigInt
+	TypeBlob
+	TypeBoolean
+	TypeCounter
+	TypeDecimal
+	TypeDouble
+	TypeFloat
+	TypeInt
+	TypeText
+	TypeTimestamp
+	TypeUUID
+	TypeVarchar
+	TypeVarint
+	TypeTimeUUID
+	TypeInet
+	TypeDate
+	TypeTime
+	TypeSmallInt
+	TypeTinyInt
+	TypeList
+	TypeSet
+	TypeMap
+	TypeTuple
+	TypeUDT
+)
+
+var typeKindNames = map[TypeKind]string{
+	TypeCustom:    "custom",
+	TypeASCII:     "ascii",
+	TypeBigInt:    "bigint",
+	TypeBlob:      "blob",
+	TypeBoolean:   "boolean",
+	TypeCounter:   "counter",
+	TypeDecimal:   "decimal",
+	TypeDouble:    "double",
+	TypeFloat:     "float",
+	TypeInt:       "int",
+	TypeText:      "text",
+	TypeTimestamp: "timestamp",
+	TypeUUID:      "uuid",
+	TypeVarchar:   "varchar",
+	TypeVarint:    "varint",
+	TypeTimeUUID:  "timeuuid",
+	TypeInet:      "inet",
+	TypeDate:      "date",
+	TypeTime:      "time",
+	TypeSmallInt:  "smallint",
+	TypeTinyInt:   "tinyint",
+	TypeList:      "list",
+	TypeSet:       "set",
+	TypeMap:       "map",
+	TypeTuple:     "tuple",
+	TypeUDT:       "udt",
+}
+
+var basicTypeKinds = map[pb.TypeSpec_Basic]TypeKind{
+	pb.TypeSpec_CUSTOM:    TypeCustom,
+	pb.TypeSpec_ASCII:     TypeASCII,
+	pb.TypeSpec_BIGINT:    TypeBigInt,
+	pb.TypeSpec_BLOB:      TypeBlob,
+	pb.TypeSpec_BOOLEAN:   TypeBoolean,
+	pb.TypeSpec_COUNTER:   TypeCounter,
+	pb.TypeSpec_DECIMAL:   TypeDecimal,
+	pb.TypeSpec_DOUBLE:    TypeDouble,
+	pb.TypeSpec_FLOAT:     TypeFloat,
+	pb.TypeSpec_INT:       TypeInt,
+	pb.TypeSpec_TEXT:      TypeText,
+	pb.TypeSpec_TIMESTAMP: TypeTimestamp,
+	pb.TypeSpec_UUID:      TypeUUID,
+	pb.TypeSpec_VARCHAR:   TypeVarchar,
+	pb.TypeSpec_VARINT:    TypeVarint,
+	pb.TypeSpec_TIMEUUID:  TypeTimeUUID,
+	pb.TypeSpec_INET:      TypeInet,
+	pb.TypeSpec_DATE:      TypeDate,
+	pb.TypeSpec_TIME:      TypeTime,
+	pb.TypeSpec_SMALLINT:  TypeSmallInt,
+	pb.TypeSpec_TINYINT:   TypeTinyInt,
+}
+
+// String returns the CQL name of the type kind, e.g. "bigint".
+func (k TypeKind) String() string {
+	if n, ok := typeKindNames[k]; ok {
+		return n
+	}
+	return fmt.Sprintf("TypeKind(%d)", uint8(k))
+}
+
+// CQLType describes a CQL type, including the types of any nested elements.
+type CQLType struct {
+	Kind TypeKind
+	// Key is the key type of a map.
+	Key *CQLType
+	// Elem is the element type of a list or set, or the value type of a map.
+	Elem *CQLType
+	// Elems are the element types of a tuple.
+	Elems []*CQLType
+	// Fields are the field types of a UDT, keyed by field name.
+	Fields map[string]*CQLType
+}
+
+// String returns a CQL-like representation of the type, e.g.
+// "map<text, list<int>>". UDTs are represented with their fields in name
+// order, e.g. "udt<street text, zip int>".
+func (t *CQLType) String() string {
+	if t == nil {
+		return "<nil>"
+	}
+	switch t.Kind {
+	case TypeList, TypeSet:
+		return fmt.Sprintf("%s<%s>", t.Kind, t.Elem)
+	case TypeMap:
+		return fmt.Sprintf("%s<%s, %s>", t.Kind, t.Key, t.Elem)
+	case TypeTuple:
+		es := make([]string, len(t.Elems))
+		for i, e := range t.Elems {
+			es[i] = e.String()
+		}
+		return fmt.Sprintf("%s<%s>", t.Kind, strings.Join(es, ", "))
+	case TypeUDT:
+		names := make([]string, 0, len(t.Fields))
+		for n := range t.Fields {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		fs := make([]string, len(names))
+		for i, n := range names {
+			fs[i] = fmt.Sprintf("%s %s", n, t.Fields[n])
+		}
+		return fmt.Sprintf("%s<%s>", t.Kind, strings.Join(fs, ", "))
+	}
+	return t.Kind.String()
+}
+
+func newCQLType(spec *pb.TypeSpec) *CQLType {
+	switch s := spec.GetSpec().(type) {
+	case *pb.TypeSpec_Basic_:
+		return &CQLType{Kind: basicTypeKinds[s.Basic]}
+	case *pb.TypeSpec_List_:
+		return &CQLType{Kind: TypeList, Elem: newCQLType(s.List.GetElement())}
+	case *pb.TypeSpec_Set_:
+		return &CQLType{Kind: TypeSet, Elem: newCQLType(s.Set.GetElement())}
+	case *pb.TypeSpec_Map_:
+		return &CQLType{
+			Kind: TypeMap,
+			Key:  newCQLType(s.Map.GetKey()),
+			Elem: newCQLType(s.Map.GetValue()),
+		}
+	case *pb.TypeSpec_Tuple_:
+		es := make([]*CQLType, len(s.Tuple.GetElements()))
+		for i, e := range s.Tuple.GetElements() {
+			es[i] = newCQLType(e)
+		}
+		return &CQLType{Kind: TypeTuple, Elems: es}
+	case *pb.TypeSpec_Udt_:
+		fs := make(map[string]*CQLType, len(s.Udt.GetFields()))
+		for n, f := range s.Udt.GetFields() {
+			fs[n] = newCQLType(f)
+		}
+		return &CQLType{Kind: TypeUDT, Fields: fs}
+	}
+	return &CQLType{Kind: TypeCustom}
+}
+
+// Column describes a column of a query result.
+type Column struct {
+	Name string
+	Type *CQLType
+}
+
+func newColumns(specs []*pb.ColumnSpec) []Column {
+	if len(specs) == 0 {
+		return nil
+	}
+	cols := make([]Column, len(specs))
+	for i, spec := range specs {
+		cols[i] = Column{Name: spec.Name, Type: newCQLType(spec.Type)}
+	}
+	return cols
+}
+
+// parseCQLType parses a CQL type as stored in system_schema, e.g.
+// "frozen<map<text, list<int>>>". User-defined types are resolved by name
+// with udt, which may be nil. Types which are neither built in nor resolved
+// by udt, such as custom types, are of kind TypeCustom.
+func parseCQLType(s string, udt func(name string) *CQLType) (*CQLType, error) {
+	p := &typeParser{s: s, udt: udt}
+	t, err := p.parse()
+	if err == nil && p.next() != "" {
+		err = fmt.Errorf("unexpected trailing input")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CQL type %q: %w", s, err)
+	}
+	return t, nil
+}
+
+type typeParser struct {
+	s   string
+	pos int
+	udt func(name string) *CQLType
+}
+
+// next consumes and returns the next token: one of "<", ">" and ",", a quoted
+// name, or an unquoted name. It returns the empty string at the end of input.
+func (p *typeParser) next() string {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+	if p.pos >= len(p.s) {
+		return ""
+	}
+	start := p.pos
+	switch c := p.s[p.pos]; c {
+	case '<', '>', ',':
+		p.pos++
+	case '\'', '"':
+		_, p.pos = cqlscan.Next(p.s, p.pos)
+	default:
+		for p.pos < len(p.s) && !strings.ContainsRune("<>, ", rune(p.s[p.pos])) {
+			p.pos++
+		}
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *typeParser) expect(tok string) error {
+	if got := p.next(); got != tok {
+		return fmt.Errorf("expected %q, got %q", tok, got)
+	}
+	return nil
+}
+
+// params parses the comma separated type parameters between "<" and ">".
+func (p *typeParser) params() ([]*CQLType, error) {
+	if err := p.expect("<"); err != nil {
+		return nil, err
+	}
+	var res []*CQLType
+	for {
+		t, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+		switch tok := p.next(); tok {
+		case ",":
+		case ">":
+			return res, nil
+		default:
+			return nil, fmt.Errorf("expected \",\" or \">\", got %q", tok)
+		}
+	}
+}
+
+func (p *typeParser) parse() (*CQLType, error) {
+	name := p.next()
+	switch name {
+	case "", "<", ">", ",":
+		return nil, fmt.Errorf("expected type name, got %q", name)
+	}
+	var (
+		kind TypeKind
+		n    int
+	)
+	switch strings.ToLower(name) {
+	case "frozen":
+		kind, n = TypeCustom, 1
+	case "list":
+		kind, n = TypeList, 1
+	case "set":
+		kind, n = TypeSet, 1
+	case "map":
+		kind, n = TypeMap, 2
+	case "tuple":
+		kind, n = TypeTuple, -1
+	default:
+		for k, kn := range typeKindNames {
+			if k < TypeList && k != TypeCustom && kn == strings.ToLower(name) {
+				return &CQLType{Kind: k}, nil
+			}
+		}
+		if p.udt != nil {
+			if t := p.udt(strings.Trim(name, `"`)); t != nil {
+				return t, nil
+			}
+		}
+		return &CQLType{Kind: TypeCustom}, nil
+	}
+
+	ps, err := p.params()
+	if err != nil {
+		return nil, err
+	}
+	if n >= 0 && len(ps) != n {
+		return nil, fmt.Errorf("%s takes %d type parameters, got %d", name, n, len(ps))
+	}
+	switch kind {
+	case TypeList, TypeSet:
+		return &CQLType{Kind: kind, Elem: ps[0]}, nil
+	case TypeMap:
+		return &CQLType{Kind: kind, Key: ps[0], Elem: ps[1]}, nil
+	case TypeTuple:
+		return &CQLType{Kind: kind, Elems: ps}, nil
+	}
+	// frozen<T> has the same type as T.
+	return ps[0], nil
+}
@@ -0,0 +1,308 @@
+package astra
+
+import (
+	"archive/zip"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bundleFiles are the files read from a secure connect bundle.
+var bundleFiles = []string{"config.json", "ca.crt", "cert", "key"}
+
+// BundleCheck identifies a check made when validating a secure connect
+// bundle.
+type BundleCheck string
+
+// Secure connect bundle checks.
+const (
+	// BundleCheckFiles checks that the bundle contains the required files.
+	BundleCheckFiles BundleCheck = "files"
+	// BundleCheckConfig checks the host and port in config.json.
+	BundleCheckConfig BundleCheck = "config"
+	// BundleCheckCA checks that ca.crt holds CA certificates.
+	BundleCheckCA BundleCheck = "ca"
+	// BundleCheckCertificate checks that the client certificate is signed by
+	// the CA.
+	BundleCheckCertificate BundleCheck = "certificate"
+	// BundleCheckKey checks that the private key matches the client
+	// certificate.
+	BundleCheckKey BundleCheck = "key"
+	// BundleCheckExpiry checks that the certificates are currently valid.
+	BundleCheckExpiry BundleCheck = "expiry"
+)
+
+// BundleProblem is a problem found when validating a secure connect bundle.
+type BundleProblem struct {
+	Check BundleCheck
+	// File is the bundle file with the problem, if any.
+	File    string
+	Message string
+}
+
+func (p BundleProblem) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("%s: %s", p.Check, p.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", p.Check, p.File, p.Message)
+}
+
+// BundleReport is the result of validating a secure connect bundle.
+type BundleReport struct {
+	// Host and Port are the host and port in config.json.
+	Host string
+	Port int
+	// NotAfter is the time at which the first of the client and CA
+	// certificates expires, or the zero time if they could not be read.
+	NotAfter time.Time
+	// Problems are the problems found, in the order of the checks made.
+	Problems []BundleProblem
+}
+
+// Valid reports whether no problems were found.
+func (r *BundleReport) Valid() bool {
+	return len(r.Problems) == 0
+}
+
+// Err returns a *BundleError holding the problems found, or nil if there are
+// none.
+func (r *BundleReport) Err() error {
+	if r.Valid() {
+		return nil
+	}
+	return &BundleError{Problems: r.Problems}
+}
+
+// ExpiresWithin reports whether a certificate of the bundle expires within d
+// of now. Certificates which have already expired are reported as problems
+// with BundleCheckExpiry.
+func (r *BundleReport) ExpiresWithin(d time.Duration) bool {
+	return !r.NotAfter.IsZero() && time.Until(r.NotAfter) < d
+}
+
+func (r *BundleReport) addProblem(check BundleCheck, file, format string, args ...any) {
+	r.Problems = append(r.Problems, BundleProblem{Check: check, File: file, Message: fmt.Sprintf(format, args...)})
+}
+
+// BundleError is returned when loading a secure connect bundle which fails
+// validation.
+type BundleError struct {
+	Problems []BundleProblem
+}
+
+func (e *BundleError) Error() string {
+	ps := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		ps[i] = p.String()
+	}
+	return fmt.Sprintf("invalid secure connect bundle: %s", strings.Join(ps, "; "))
+}
+
+// ValidateBundle validates the secure connect bundle at path, checking that
+// config.json holds a valid host, and port if it is dialed, from which the
+// address to dial can be derived with opts, that the client certificate is
+// signed by the CA of the bundle and matches the private key, and that the
+// certificates are currently valid. The returned error is only non-nil if the
+// bundle could not be read; problems found are listed in the report.
+//
+// Bundles are also validated when creating a client, which fails with a
+// *BundleError if there are problems.
+func ValidateBundle(path string, opts ...BundleOption) (*BundleReport, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer reader.Close()
+	return validateBundleZip(&reader.Reader, opts)
+}
+
+// ValidateBundleReader is like ValidateBundle, but reads a bundle of the
+// given size from r.
+func ValidateBundleReader(r io.ReaderAt, size int64, opts ...BundleOption) (*BundleReport, error) {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	return validateBundleZip(reader, opts)
+}
+
+func validateBundleZip(reader *zip.Reader, opts []BundleOption) (*BundleReport, error) {
+	contents, err := extract(reader)
+	if err != nil {
+		return nil, err
+	}
+	report, _ := validateBundle(contents, time.Now(), newBundleOptions(opts))
+	return report, nil
+}
+
+// bundleContents are the validated contents of a secure connect bundle.
+type bundleContents struct {
+	// host and port are the address to dial.
+	host string
+	port int
+	// serverName is the host name verified against the server certificate.
+	serverName string
+	caPEM      []byte
+	cert       tls.Certificate
+}
+
+// validateBundle validates the contents of a secure connect bundle at now,
+// resolving the address to dial with o. The returned bundleContents are only
+// complete if the report is valid.
+func validateBundle(contents map[string][]byte, now time.Time, o *bundleOptions) (*BundleReport, *bundleContents) {
+	r := &BundleReport{}
+	bc := &bundleContents{}
+
+	for _, file := range bundleFiles {
+		if _, ok := contents[file]; !ok {
+			r.addProblem(BundleCheckFiles, file, "missing from bundle")
+		}
+	}
+
+	if b, ok := contents["config.json"]; ok {
+		var config struct {
+			Host string `json:"host"`
+			Port int    `json:"port"`
+		}
+		if err := json.Unmarshal(b, &config); err != nil {
+			r.addProblem(BundleCheckConfig, "config.json", "failed to parse: %v", err)
+		} else {
+			r.Host, r.Port = config.Host, config.Port
+			valid := true
+			switch {
+			case config.Host == "":
+				r.addProblem(BundleCheckConfig, "config.json", "missing host")
+				valid = false
+			case strings.Contains(config.Host, ":"):
+				r.addProblem(BundleCheckConfig, "config.json", "host %q includes a port, which must be given separately", config.Host)
+				valid = false
+			}
+			if o.usesConfigPort() && (config.Port <= 0 || config.Port > 65535) {
+				r.addProblem(BundleCheckConfig, "config.json", "port %d is out of range", config.Port)
+				valid = false
+			}
+			if valid {
+				host, port, err := o.resolve(config.Host, config.Port)
+				switch {
+				case err != nil:
+					r.addProblem(BundleCheckConfig, "config.json", "failed to resolve address: %v", err)
+				case host == "" || port <= 0 || port > 65535:
+					r.addProblem(BundleCheckConfig, "config.json", "resolved invalid address %q", net.JoinHostPort(host, strconv.Itoa(port)))
+				default:
+					bc.host, bc.port = host, port
+					bc.serverName = o.serverName(config.Host, host)
+				}
+			}
+		}
+	}
+
+	var cas []*x509.Certificate
+	if b, ok := contents["ca.crt"]; ok {
+		bc.caPEM = b
+		var err error
+		if cas, err = parseCertificates(b); err != nil {
+			r.addProblem(BundleCheckCA, "ca.crt", "failed to parse: %v", err)
+		} else if len(cas) == 0 {
+			r.addProblem(BundleCheckCA, "ca.crt", "no certificates found")
+		}
+		for _, ca := range cas {
+			if !ca.IsCA {
+				r.addProblem(BundleCheckCA, "ca.crt", "certificate %q is not a CA certificate", ca.Subject)
+			}
+		}
+	}
+
+	var leaf *x509.Certificate
+	if b, ok := contents["cert"]; ok {
+		certs, err := parseCertificates(b)
+		switch {
+		case err != nil:
+			r.addProblem(BundleCheckCertificate, "cert", "failed to parse: %v", err)
+		case len(certs) == 0:
+			r.addProblem(BundleCheckCertificate, "cert", "no certificates found")
+		default:
+			leaf = certs[0]
+		}
+	}
+	if leaf != nil && len(cas) > 0 {
+		roots := x509.NewCertPool()
+		for _, ca := range cas {
+			roots.AddCert(ca)
+		}
+		// Validity periods are reported by the expiry check.
+		at := now
+		if at.After(leaf.NotAfter) {
+			at = leaf.NotAfter
+		} else if at.Before(leaf.NotBefore) {
+			at = leaf.NotBefore
+		}
+		_, err := leaf.Verify(x509.VerifyOptions{
+			Roots:       roots,
+			CurrentTime: at,
+			KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		var invalid x509.CertificateInvalidError
+		if err != nil && !(errors.As(err, &invalid) && invalid.Reason == x509.Expired) {
+			r.addProblem(BundleCheckCertificate, "cert", "not signed by the bundle CA: %v", err)
+		}
+	}
+
+	if certPEM, ok := contents["cert"]; ok && leaf != nil {
+		if keyPEM, ok := contents["key"]; ok {
+			cert, err := tls.X509KeyPair(certPEM, keyPEM)
+			if err != nil {
+				r.addProblem(BundleCheckKey, "key", "does not match the client certificate: %v", err)
+			}
+			bc.cert = cert
+		}
+	}
+
+	for _, c := range append([]*x509.Certificate{leaf}, cas...) {
+		if c == nil {
+			continue
+		}
+		if r.NotAfter.IsZero() || c.NotAfter.Before(r.NotAfter) {
+			r.NotAfter = c.NotAfter
+		}
+		file := "ca.crt"
+		if c == leaf {
+			file = "cert"
+		}
+		switch {
+		case now.After(c.NotAfter):
+			r.addProblem(BundleCheckExpiry, file, "certificate %q expired at %s", c.Subject, c.NotAfter.Format(time.RFC3339))
+		case now.Before(c.NotBefore):
+			r.addProblem(BundleCheckExpiry, file, "certificate %q is not valid until %s", c.Subject, c.NotBefore.Format(time.RFC3339))
+		}
+	}
+
+	return r, bc
+}
+
+// parseCertificates parses the PEM-encoded certificates in b.
+func parseCertificates(b []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, b = pem.Decode(b)
+		if block == nil {
+			return certs, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, c)
+	}
+}
@@ -0,0 +1,159 @@
+package astra
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidateBundle(t *testing.T) {
+	const host = "0123-us-east1.db.astra.datastax.com"
+	now := time.Now()
+	valid := func() map[string][]byte { return testBundleFiles(t, host, now.Add(24*time.Hour)) }
+	other := testBundleFiles(t, host, now.Add(24*time.Hour))
+
+	tests := []struct {
+		name   string
+		files  func() map[string][]byte
+		opts   []BundleOption
+		checks []BundleCheck
+	}{
+		{
+			name:  "valid",
+			files: valid,
+		},
+		{
+			name: "missing file",
+			files: func() map[string][]byte {
+				fs := valid()
+				delete(fs, "ca.crt")
+				return fs
+			},
+			checks: []BundleCheck{BundleCheckFiles},
+		},
+		{
+			name: "bad host and port",
+			files: func() map[string][]byte {
+				fs := valid()
+				fs["config.json"] = []byte(`{"host": "localhost:9042", "port": 0}`)
+				return fs
+			},
+			opts:   []BundleOption{BundleExactHost()},
+			checks: []BundleCheck{BundleCheckConfig, BundleCheckConfig},
+		},
+		{
+			name: "bad port not dialed",
+			files: func() map[string][]byte {
+				fs := valid()
+				fs["config.json"] = []byte(`{"host": "` + host + `", "port": 0}`)
+				return fs
+			},
+		},
+		{
+			name: "bad config port",
+			files: func() map[string][]byte {
+				fs := valid()
+				fs["config.json"] = []byte(`{"host": "` + host + `", "port": 65536}`)
+				return fs
+			},
+			opts:   []BundleOption{BundleConfigPort()},
+			checks: []BundleCheck{BundleCheckConfig},
+		},
+		{
+			name: "unqualified host",
+			files: func() map[string][]byte {
+				fs := valid()
+				fs["config.json"] = []byte(`{"host": "localhost", "port": 29042}`)
+				return fs
+			},
+			checks: []BundleCheck{BundleCheckConfig},
+		},
+		{
+			name: "other CA",
+			files: func() map[string][]byte {
+				fs := valid()
+				fs["ca.crt"] = other["ca.crt"]
+				return fs
+			},
+			checks: []BundleCheck{BundleCheckCertificate},
+		},
+		{
+			name: "mismatched key",
+			files: func() map[string][]byte {
+				fs := valid()
+				fs["key"] = other["key"]
+				return fs
+			},
+			checks: []BundleCheck{BundleCheckKey},
+		},
+		{
+			name: "client certificate is a CA",
+			files: func() map[string][]byte {
+				fs := valid()
+				fs["cert"] = fs["ca.crt"]
+				return fs
+			},
+			checks: []BundleCheck{BundleCheckKey},
+		},
+		{
+			name:   "expired",
+			files:  func() map[string][]byte { return testBundleFiles(t, host, now.Add(-time.Minute)) },
+			checks: []BundleCheck{BundleCheckExpiry},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := validateBundle(tt.files(), now, newBundleOptions(tt.opts))
+			var got []BundleCheck
+			for _, p := range r.Problems {
+				got = append(got, p.Check)
+			}
+			if diff := cmp.Diff(tt.checks, got); diff != "" {
+				t.Errorf("validateBundle() problem checks mismatch (-want +got):\n%s\nproblems: %v", diff, r.Problems)
+			}
+			if r.Valid() != (len(tt.checks) == 0) {
+				t.Errorf("Valid() got %t, want %t", r.Valid(), len(tt.checks) == 0)
+			}
+		})
+	}
+}
+
+func TestValidateBundleReader(t *testing.T) {
+	notAfter := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	b := testBundle(t, "0123-us-east1.db.astra.datastax.com", notAfter)
+
+	r, err := ValidateBundleReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatalf("ValidateBundleReader() unexpected error: %v", err)
+	}
+	if !r.Valid() {
+		t.Fatalf("ValidateBundleReader() got problems %v, want none", r.Problems)
+	}
+	if r.Host != "0123-us-east1.db.astra.datastax.com" || r.Port != 29042 {
+		t.Errorf("ValidateBundleReader() got host %q and port %d", r.Host, r.Port)
+	}
+	if !r.NotAfter.Equal(notAfter) {
+		t.Errorf("NotAfter got %v, want %v", r.NotAfter, notAfter)
+	}
+	if !r.ExpiresWithin(48*time.Hour) || r.ExpiresWithin(time.Hour) {
+		t.Errorf("ExpiresWithin() got wrong result for NotAfter %v", r.NotAfter)
+	}
+}
+
+func TestLoadBundle_invalid(t *testing.T) {
+	files := testBundleFiles(t, "0123-us-east1.db.astra.datastax.com", time.Now().Add(24*time.Hour))
+	delete(files, "key")
+
+	_, err := loadBundleZipFromBytes(zipBundle(t, files), nil)
+	var berr *BundleError
+	if !errors.As(err, &berr) {
+		t.Fatalf("loadBundleZipFromBytes() got error %v, want *BundleError", err)
+	}
+	want := []BundleProblem{{Check: BundleCheckFiles, File: "key", Message: "missing from bundle"}}
+	if diff := cmp.Diff(want, berr.Problems); diff != "" {
+		t.Errorf("BundleError.Problems mismatch (-want +got):\n%s", diff)
+	}
+}
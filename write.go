@@ -0,0 +1,188 @@
+package astra
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type unsetValue struct{}
+
+// Unset is a value which leaves the column it is bound to unchanged, rather
+// than writing a null which would create a tombstone. Struct fields tagged
+// with the "omitempty" option are bound as Unset when they hold their zero
+// value.
+var Unset = unsetValue{}
+
+// UDT is a UDT value written from a map of UDT field names to values. Other
+// maps, including a map[string]any, are written as CQL maps. UDT values are
+// read as a map[string]any.
+//
+//	_, err := c.Query("INSERT INTO users (id, home) VALUES (?, ?)", id,
+//	    astra.UDT{"street": "Main St", "zip_code": 12345}).Exec()
+type UDT map[string]any
+
+// structColumn is a column value taken from a struct field.
+type structColumn struct {
+	name  string
+	value any
+	key   bool
+}
+
+// structColumns returns the column values of the fields of the struct v, or
+// pointed to by v, mapped by cqlFields. Fields holding their zero value and
+// tagged with the "omitempty" option, and fields unreachable through a nil
+// embedded pointer, are Unset.
+func structColumns(v any) ([]structColumn, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("value must be a non-nil struct or pointer to a struct, got %T", v)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("value must be a non-nil struct or pointer to a struct, got %T", v)
+	}
+	fs := cqlFields(rv.Type())
+	res := make([]structColumn, len(fs))
+	for i, f := range fs {
+		res[i] = structColumn{name: f.name, value: Unset, key: f.key}
+		if fv, ok := fieldByIndex(rv, f.index); ok && !(f.omitEmpty && fv.IsZero()) {
+			res[i].value = fv.Interface()
+		}
+	}
+	return res, nil
+}
+
+// WriteOption is an option for the statements created by Client.Insert and
+// Client.Update.
+type WriteOption func(*writeOptions)
+
+type writeOptions struct {
+	ttl time.Duration
+}
+
+// TTL sets the time to live of the written values, rounded down to the
+// second. A TTL under one second is rejected, since a TTL of 0 means the
+// values never expire.
+func TTL(ttl time.Duration) WriteOption {
+	return func(o *writeOptions) {
+		o.ttl = ttl
+	}
+}
+
+func (o *writeOptions) using() (string, error) {
+	if o.ttl <= 0 {
+		return "", nil
+	}
+	if o.ttl < time.Second {
+		return "", fmt.Errorf("TTL %v is less than one second", o.ttl)
+	}
+	return fmt.Sprintf(" USING TTL %d", int64(o.ttl/time.Second)), nil
+}
+
+// Insert creates a new Astra query inserting the fields of the struct v, or
+// pointed to by v, as a row of table. Columns are named after the fields by
+// their `cql` tag or, if untagged, the snake case form of the field name.
+// Fields tagged with the "omitempty" option are left unset when they hold
+// their zero value. Struct fields are written as UDTs, and slice and map
+// fields as collections.
+//
+//	type User struct {
+//	    ID    uuid.UUID `cql:"id,key"`
+//	    Name  string
+//	    Email string    `cql:"email,omitempty"`
+//	}
+//
+//	_, err := c.Insert("users", &u, astra.TTL(24*time.Hour)).Exec()
+func (c *Client) Insert(table string, v any, opts ...WriteOption) *Query {
+	q := c.Query("")
+	cols, err := structColumns(v)
+	if err != nil {
+		q.err = fmt.Errorf("failed to create insert query: %w", err)
+		return q
+	}
+	var o writeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	using, err := o.using()
+	if err != nil {
+		q.err = fmt.Errorf("failed to create insert query: %w", err)
+		return q
+	}
+
+	names := make([]string, len(cols))
+	markers := make([]string, len(cols))
+	for i, col := range cols {
+		names[i] = quoteIdent(col.name)
+		markers[i] = "?"
+		q.values = append(q.values, col.value)
+	}
+	q.cql = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)%s",
+		table, strings.Join(names, ", "), strings.Join(markers, ", "), using)
+	return q
+}
+
+// Update creates a new Astra query updating the row of table identified by
+// the fields of the struct v, or pointed to by v, tagged with the "key"
+// option, setting the columns of the other fields. Fields are mapped to
+// columns as by Insert.
+//
+//	_, err := c.Update("users", &User{ID: id, Name: "Alice"}).Exec()
+func (c *Client) Update(table string, v any, opts ...WriteOption) *Query {
+	q := c.Query("")
+	cols, err := structColumns(v)
+	if err != nil {
+		q.err = fmt.Errorf("failed to create update query: %w", err)
+		return q
+	}
+	var o writeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	using, err := o.using()
+	if err != nil {
+		q.err = fmt.Errorf("failed to create update query: %w", err)
+		return q
+	}
+
+	var sets, conds []string
+	var keyValues []any
+	for _, col := range cols {
+		if col.key {
+			conds = append(conds, quoteIdent(col.name)+" = ?")
+			keyValues = append(keyValues, col.value)
+			continue
+		}
+		sets = append(sets, quoteIdent(col.name)+" = ?")
+		q.values = append(q.values, col.value)
+	}
+	if len(conds) == 0 {
+		q.err = fmt.Errorf("failed to create update query: %T has no fields tagged with the \"key\" option", v)
+		return q
+	}
+	if len(sets) == 0 {
+		q.err = fmt.Errorf("failed to create update query: %T has no fields to set", v)
+		return q
+	}
+	q.values = append(q.values, keyValues...)
+	q.cql = fmt.Sprintf("UPDATE %s%s SET %s WHERE %s",
+		table, using, strings.Join(sets, ", "), strings.Join(conds, " AND "))
+	return q
+}
+
+// quoteIdent returns name as a CQL identifier, quoted if it is not a valid
+// unquoted identifier or contains upper case letters.
+func quoteIdent(name string) string {
+	unquoted := name != "" && isIdentStart(name[0])
+	for i := 0; unquoted && i < len(name); i++ {
+		unquoted = isIdentPart(name[i]) && !('A' <= name[i] && name[i] <= 'Z')
+	}
+	if unquoted {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
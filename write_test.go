@@ -0,0 +1,122 @@
+package astra
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	pb "github.com/stargate/stargate-grpc-go-client/stargate/pkg/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+type testWriteUser struct {
+	ID      int         `cql:"id,key"`
+	Name    string      `cql:"name"`
+	Email   string      `cql:"email,omitempty"`
+	Home    testAddress `cql:"home"`
+	Tags    []string    `cql:"tags,omitempty"`
+	Ignored string      `cql:"-"`
+}
+
+func TestClient_Insert(t *testing.T) {
+	c := &Client{}
+	home := testAddress{Street: "Main St", ZipCode: 12345}
+
+	q := c.Insert("ks.users", &testWriteUser{ID: 1, Name: "Alice", Home: home}, TTL(90*time.Second))
+	if err := q.err; err != nil {
+		t.Fatalf("Insert() unexpected error: %v", err)
+	}
+	wantCQL := `INSERT INTO ks.users (id, name, email, home, tags) VALUES (?, ?, ?, ?, ?) USING TTL 90`
+	if q.cql != wantCQL {
+		t.Errorf("Insert() got CQL %q, want %q", q.cql, wantCQL)
+	}
+	wantValues := []any{1, "Alice", Unset, home, Unset}
+	if diff := cmp.Diff(wantValues, q.values, cmp.AllowUnexported(unsetValue{})); diff != "" {
+		t.Errorf("Insert() values mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := c.Insert("users", 1).toQueryProto(); err == nil {
+		t.Errorf("Insert() of non-struct got nil error, want error")
+	}
+}
+
+func TestClient_Update(t *testing.T) {
+	c := &Client{}
+
+	q := c.Update("users", testWriteUser{ID: 1, Name: "Alice", Email: "alice@example.com"})
+	if err := q.err; err != nil {
+		t.Fatalf("Update() unexpected error: %v", err)
+	}
+	wantCQL := `UPDATE users SET name = ?, email = ?, home = ?, tags = ? WHERE id = ?`
+	if q.cql != wantCQL {
+		t.Errorf("Update() got CQL %q, want %q", q.cql, wantCQL)
+	}
+	wantValues := []any{"Alice", "alice@example.com", testAddress{}, Unset, 1}
+	if diff := cmp.Diff(wantValues, q.values, cmp.AllowUnexported(unsetValue{})); diff != "" {
+		t.Errorf("Update() values mismatch (-want +got):\n%s", diff)
+	}
+
+	type noKey struct {
+		Name string
+	}
+	if _, err := c.Update("users", noKey{}).toQueryProto(); err == nil {
+		t.Errorf("Update() of struct without key got nil error, want error")
+	}
+}
+
+func TestTTL_subSecond(t *testing.T) {
+	c := &Client{}
+	u := &testWriteUser{ID: 1, Name: "Alice"}
+	if _, err := c.Insert("users", u, TTL(500*time.Millisecond)).toQueryProto(); err == nil {
+		t.Errorf("Insert() with TTL under one second got nil error, want error")
+	}
+	if _, err := c.Update("users", u, TTL(time.Millisecond)).toQueryProto(); err == nil {
+		t.Errorf("Update() with TTL under one second got nil error, want error")
+	}
+}
+
+func TestQuery_BindStruct(t *testing.T) {
+	c := &Client{}
+	q := c.Query(`UPDATE users USING TTL :ttl SET email = :email WHERE id = :id`).
+		BindStruct(&testWriteUser{ID: 1}).
+		Bind(map[string]any{"ttl": 60})
+
+	got, err := q.toQueryProto()
+	if err != nil {
+		t.Fatalf("toQueryProto() unexpected error: %v", err)
+	}
+	want := &pb.Query{
+		Cql: `UPDATE users USING TTL ? SET email = ? WHERE id = ?`,
+		Values: &pb.Values{Values: []*pb.Value{
+			{Inner: &pb.Value_Int{Int: 60}},
+			{Inner: &pb.Value_Unset_{Unset: &pb.Value_Unset{}}},
+			{Inner: &pb.Value_Int{Int: 1}},
+		}},
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("toQueryProto() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestQuery_BindStruct_positional(t *testing.T) {
+	c := &Client{}
+	q := c.Query(`UPDATE users SET email = ? WHERE id = ?`).BindStruct(&testWriteUser{ID: 1})
+	if _, err := q.toQueryProto(); err == nil {
+		t.Errorf("toQueryProto() of struct bound to positional markers got nil error, want error")
+	}
+}
+
+func TestQuoteIdent(t *testing.T) {
+	tests := map[string]string{
+		"name":       "name",
+		"user_id":    "user_id",
+		"UserID":     `"UserID"`,
+		"first name": `"first name"`,
+		`a"b`:        `"a""b"`,
+	}
+	for in, want := range tests {
+		if got := quoteIdent(in); got != want {
+			t.Errorf("quoteIdent(%q) got %s, want %s", in, got, want)
+		}
+	}
+}